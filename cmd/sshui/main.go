@@ -1,21 +1,45 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
-	"os/exec"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	gss "github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/moneronodo/sshui/internal/backend/daemonrpc"
+	"github.com/moneronodo/sshui/internal/backend/demo"
 	"github.com/moneronodo/sshui/internal/backend/i_dbus"
+	"github.com/moneronodo/sshui/internal/backend/i_moneropay"
+	"github.com/moneronodo/sshui/internal/backend/i_systemd"
+	i_lws "github.com/moneronodo/sshui/internal/backend/lws"
 	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/model/dbus"
 	"github.com/moneronodo/sshui/internal/screens"
 )
 
 const TabAreaWid int = 12
 
-var prog *tea.Program
+var (
+	prog      *tea.Program
+	logger    = base.Logger("ui")
+	configLog = base.Logger("config")
+)
+
+// Scrolls the lines of a ScrollScreen, arrows and letters are taken
+var scrollKeys = viewport.KeyMap{
+	PageDown:     key.NewBinding(key.WithKeys("pgdown")),
+	PageUp:       key.NewBinding(key.WithKeys("pgup")),
+	HalfPageDown: key.NewBinding(key.WithKeys("ctrl+d")),
+	HalfPageUp:   key.NewBinding(key.WithKeys("ctrl+u")),
+}
 
 type model struct {
 	screens     []screens.Screen
@@ -24,6 +48,7 @@ type model struct {
 	width       int
 	height      int
 	firstBoot   bool
+	services    screens.ServiceManager
 	styles      *base.Styles
 	tabsPort    viewport.Model
 	contentPort viewport.Model
@@ -57,14 +82,6 @@ func (m *model) updateStyles() {
 	)
 }
 
-func closePopup() {
-	if len(screens.Popups) <= 1 {
-		screens.Popups = nil
-	} else {
-		screens.Popups = screens.Popups[1:]
-	}
-}
-
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var (
 		cmds []tea.Cmd
@@ -72,7 +89,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	)
 	switch mt := msg.(type) {
 	case base.ConfigSavedMsg:
-		exec.Command("/usr/bin/systemctl", "restart", "monerod")
+		cmds = append(cmds, screens.RestartServices(m.services, mt.Services))
+	case screens.ServiceJobMsg:
+		cmds = append(cmds, mt.Continue())
+	case dbus.BusStatusMsg:
+		screens.UpdateBusStatus(mt)
+	case base.ConfigFileMsg:
+		changes, err := base.ReloadConfig()
+		if err != nil {
+			configLog.Error("reloading config failed", "err", err)
+			return m, nil
+		}
+		if len(changes) > 0 {
+			return m, func() tea.Msg {
+				return base.ConfigChangedMsg{Changes: changes}
+			}
+		}
+		return m, nil
+	case base.ConfigChangedMsg:
+		screens.RebindConfig(mt.Changes)
 	case tea.WindowSizeMsg:
 		m.width = mt.Width
 		m.height = mt.Height
@@ -81,7 +116,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.tabsPort.Width = TabAreaWid - tabsizeX
 		m.tabsPort.Height = m.height - tabsizeY
 		m.contentPort.Width = (m.width - TabAreaWid) - contentsizeX - 4
-		m.contentPort.Height = m.height - contentsizeY
+		// the last line is the status bar
+		m.contentPort.Height = m.height - contentsizeY - 1
 
 		m.tabsPort, cmd = m.tabsPort.Update(msg)
 		cmds = append(cmds, cmd)
@@ -90,6 +126,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 		m.updateStyles()
 	case tea.KeyMsg:
+		// nothing else to do while the device erases itself
+		if screens.FactoryResetting() {
+			switch mt.String() {
+			case "enter":
+				screens.DismissFactoryReset()
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
 		if len(screens.Popups) > 0 {
 			curpopup := screens.Popups[0]
 			switch mt.String() {
@@ -98,14 +144,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "down":
 				return m, curpopup.Next
 			case "esc", "ctrl+c":
-				closePopup()
+				screens.ClosePopup(curpopup)
 				return m, nil
 			case "enter":
 				c := curpopup.Interact(m)
 				if len(curpopup.Items()) > 0 {
 					switch curpopup.Items()[curpopup.Current()].(type) {
 					case *screens.ScreenButton:
-						closePopup()
+						screens.ClosePopup(curpopup)
 					}
 				}
 				return m, c
@@ -115,7 +161,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		curscreen := m.screens[m.current]
+		if _, ok := curscreen.(screens.ScrollScreen); ok && m.active &&
+			key.Matches(mt, scrollKeys.PageDown, scrollKeys.PageUp,
+				scrollKeys.HalfPageDown, scrollKeys.HalfPageUp) {
+			return m, m.syncScroll(msg)
+		}
 		switch mt.String() {
+		case "ctrl+s":
+			screens.AddPopup(screens.NewPendingPopup())
+			return m, nil
 		case "down", "tab":
 			if m.active {
 				return m, curscreen.Next
@@ -125,7 +179,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.current = 0
 				}
 				m.updateStyles()
-				return m, nil
+				return m, m.sendActive
 			}
 		case "up", "shift+tab":
 			if m.active {
@@ -136,7 +190,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.current = len(m.screens) - 1
 				}
 				m.updateStyles()
-				return m, nil
+				return m, m.sendActive
 			}
 		case "enter":
 			if m.active {
@@ -157,6 +211,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.active && len(m.screens) > 1 {
 				m.active = false
 				return m, m.sendActive
+			} else if len(base.PendingChanges()) > 0 {
+				screens.AddPopup(screens.NewQuitPopup())
+				return m, nil
 			} else {
 				return m, tea.Quit
 			}
@@ -171,13 +228,42 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		vu := v.Update(msg, m)
 		if vu != nil {
-			cmds = append(cmds, v.Update(msg, m))
+			cmds = append(cmds, vu)
 		}
 	}
+	cmds = append(cmds, m.syncScroll(nil))
 
 	return m, tea.Batch(cmds...)
 }
 
+// contentPort sized for the lines of s under header, which is its items
+func (m model) scrollPort(s screens.ScrollScreen, header string) viewport.Model {
+	vp := m.contentPort
+	vp.Height = max(1, vp.Height-gss.Height(header))
+	vp.SetContent(strings.Join(s.Content(), "\n"))
+	return vp
+}
+
+// Scrolls the current screen's lines by msg, or keeps up with them when msg
+// is nil, and tells the screen what's in view
+func (m *model) syncScroll(msg tea.Msg) tea.Cmd {
+	s, ok := m.screens[m.current].(screens.ScrollScreen)
+	if !ok {
+		return nil
+	}
+	s.View()
+	var cmd tea.Cmd
+	vp := m.scrollPort(s, m.renderItems(colActive))
+	if msg != nil {
+		vp, cmd = vp.Update(msg)
+	} else if s.Follow() {
+		vp.GotoBottom()
+	}
+	m.contentPort.YOffset = vp.YOffset
+	s.Scrolled(vp.YOffset, vp.Height)
+	return cmd
+}
+
 func (m *model) sendActive() tea.Msg {
 	return screens.ScreenActiveChangeMsg{
 		Active: m.active,
@@ -188,10 +274,14 @@ func (m *model) sendActive() tea.Msg {
 func (m model) renderTabs(col gss.Color) string {
 	var sb []string
 	for i, s := range m.screens {
+		label := s.Label()
+		if n := screens.Unread(label); n > 0 {
+			label += fmt.Sprintf(" (%d)", n)
+		}
 		if i == m.current {
-			sb = append(sb, m.styles.TabsHg.Background(col).Render(s.Label()))
+			sb = append(sb, m.styles.TabsHg.Background(col).Render(label))
 		} else {
-			sb = append(sb, m.styles.Tabs.Foreground(col).Render(s.Label()))
+			sb = append(sb, m.styles.Tabs.Foreground(col).Render(label))
 		}
 	}
 	return gss.JoinVertical(gss.Left, sb...)
@@ -200,6 +290,21 @@ func (m model) renderTabs(col gss.Color) string {
 const colActive = gss.Color(base.CWhite)
 const colInactive = gss.Color(base.CGray)
 
+func (m model) renderItems(cont gss.Color) string {
+	var it []string
+	for _, i := range m.screens[m.current].Items() {
+		if i.IsFocus() {
+			it = append(it, m.styles.ContentItem.Foreground(cont).BorderForeground(i.GetColor()).Render(i.Render()))
+		} else {
+			it = append(it, m.styles.ContentItem.Foreground(cont).BorderForeground(colInactive).Render(i.Render()))
+		}
+	}
+	if m.screens[m.current].Vertical() {
+		return gss.JoinVertical(gss.Left, it...)
+	}
+	return gss.JoinHorizontal(gss.Top, it...)
+}
+
 func (m model) View() string {
 	if m.width <= 0 || m.height <= 0 {
 		return ""
@@ -211,10 +316,10 @@ func (m model) View() string {
 	if m.width < TabAreaWid*2 || m.height < len(m.screens) {
 		return "..."
 	}
-	var (
-		it        []string
-		tab, cont gss.Color
-	)
+	if screens.FactoryResetting() {
+		return gss.Place(m.width, m.height, gss.Center, gss.Center, screens.FactoryResetView())
+	}
+	var tab, cont gss.Color
 	if m.active {
 		tab = colInactive
 		cont = colActive
@@ -223,21 +328,15 @@ func (m model) View() string {
 		cont = colInactive
 	}
 	m.screens[m.current].View()
-	for _, i := range m.screens[m.current].Items() {
-		if i.IsFocus() {
-			it = append(it, m.styles.ContentItem.Foreground(cont).BorderForeground(i.GetColor()).Render(i.Render()))
-		} else {
-			it = append(it, m.styles.ContentItem.Foreground(cont).BorderForeground(colInactive).Render(i.Render()))
-		}
-	}
+	items := m.renderItems(cont)
 	var sv string
-	if m.screens[m.current].Vertical() {
+	if s, ok := m.screens[m.current].(screens.ScrollScreen); ok {
 		sv = gss.Place(
 			m.contentPort.Width,
 			m.contentPort.Height,
-			m.screens[m.current].PosHorizontal(),
-			m.screens[m.current].PosVertical(),
-			gss.JoinVertical(gss.Left, it...),
+			s.PosHorizontal(),
+			gss.Top,
+			gss.JoinVertical(gss.Left, items, m.scrollPort(s, items).View()),
 		)
 	} else {
 		sv = gss.Place(
@@ -245,7 +344,7 @@ func (m model) View() string {
 			m.contentPort.Height,
 			m.screens[m.current].PosHorizontal(),
 			m.screens[m.current].PosVertical(),
-			gss.JoinHorizontal(gss.Top, it...),
+			items,
 		)
 	}
 	var popups = ""
@@ -260,42 +359,119 @@ func (m model) View() string {
 				Render(screens.Popups[0].Render()),
 		)
 	}
-	return gss.JoinHorizontal(
-		gss.Top,
-		m.styles.TabArea.Render(m.renderTabs(tab)),
-		m.styles.ContentArea.Foreground(cont).BorderForeground(cont).Render(sv),
-	) + popups
+	view := gss.JoinVertical(
+		gss.Left,
+		gss.JoinHorizontal(
+			gss.Top,
+			m.styles.TabArea.Render(m.renderTabs(tab)),
+			m.styles.ContentArea.Foreground(cont).BorderForeground(cont).Render(sv),
+		),
+		statusBar(),
+	)
+	return overlayTopRight(view, screens.Toasts(), m.width) + popups
 }
 
+// Draws top over the top right corner of view, which is width wide
+func overlayTopRight(view, top string, width int) string {
+	if top == "" {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	for i, tl := range strings.Split(top, "\n") {
+		if i >= len(lines) {
+			break
+		}
+		left := width - gss.Width(tl)
+		l := ansi.Truncate(lines[i], left, "")
+		lines[i] = l + strings.Repeat(" ", max(0, left-gss.Width(l))) + tl
+	}
+	return strings.Join(lines, "\n")
+}
 
+// Whichever of the bus state and the unsaved changes there is to tell
+func statusBar() string {
+	var parts []string
+	for _, s := range []string{screens.BusStatus(), screens.PendingStatus()} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "  ")
+}
 
-func initModel() model {
-	var dump *os.File
-	var err error
-	dump, err = os.OpenFile("messages.log", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
-	if err != nil {
-		os.Exit(1)
+
+
+type backends struct {
+	daemon screens.DaemonClient
+	bus    screens.SystemBus
+	// passes the signals of the bus to the program
+	listen    func(prog *tea.Program)
+	lws       screens.LWSAdmin
+	moneropay screens.MoneropayStore
+	systemd   screens.ServiceManager
+	// connections to close on the way out
+	closers []io.Closer
+}
+
+func newBackends() backends {
+	bus, systemd := i_dbus.NewNodo(), i_systemd.NewManager()
+	return backends{
+		daemon: daemonrpc.NewClient(base.Opts.DaemonUrl),
+		bus:    bus,
+		listen: i_dbus.Signals,
+		lws:    i_lws.Admin{},
+		moneropay: i_moneropay.Store{
+			Url: base.Opts.MoneropayUrl,
+			DB:  base.Opts.MoneropayDB,
+		},
+		systemd: systemd,
+		closers: []io.Closer{bus, systemd},
+	}
+}
+
+func demoBackends(d *demo.Backends) backends {
+	return backends{
+		daemon:    d.Daemon,
+		bus:       d.Bus,
+		listen:    d.Listen,
+		lws:       d.LWS,
+		moneropay: d.Moneropay,
+		systemd:   d.Systemd,
 	}
-	base.Dump = dump
+}
+
+func initModel(b backends) model {
 	m := model{
 		screens:   []screens.Screen{},
 		current:   0,
 		active:    false,
 		firstBoot: base.IsFirstBoot(),
+		services:  b.systemd,
 		styles:    base.InitStyles(1, 0, 0, 1),
 	}
+	m.contentPort.KeyMap = scrollKeys
 	if m.firstBoot {
 		m.screens = append(m.screens,
-			screens.NewFirstBoot(),
+			screens.NewFirstBoot(b.bus),
 		)
 		m.active = true
 	} else {
 		m.screens = append(m.screens,
 			screens.NewDashboard(),
+			screens.NewPeers(),
+			screens.NewBans(b.daemon),
+			screens.NewBlocks(b.daemon),
+			screens.NewTxPool(b.daemon),
 			screens.NewNode(),
 			screens.NewSettings(),
-			screens.NewSystem(),
-			screens.NewLightWallet(),
+			screens.NewHistory(),
+			screens.NewSystem(b.bus),
+			screens.NewRecovery(b.bus),
+			screens.NewServices(b.systemd),
+			screens.NewLogs(b.systemd),
+			screens.NewDiagnostics(),
+			screens.NewNotifications(),
+			screens.NewLightWallet(b.lws),
 			screens.NewMoneropay(),
 			screens.NewDropToShell(),
 		)
@@ -314,15 +490,52 @@ func initModel() model {
 }
 
 func main() {
-	f, err := tea.LogToFile("debug.log", "dbg:")
+	if err := base.ParseOptions(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	// from a shell sshui dropped to, exiting that gets back to it
+	if os.Getenv(screens.SubshellEnv) != "" {
+		fmt.Fprintln(os.Stderr, "sshui is already running, type exit to get back to it")
+		os.Exit(1)
+	}
+	if err := base.InitLog(); err != nil {
+		fmt.Fprintln(os.Stderr, "log:", err)
+		os.Exit(1)
+	}
+	var b backends
+	if base.Opts.Demo {
+		d, cleanup, err := demo.Start()
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer cleanup()
+		b = demoBackends(d)
+	} else {
+		b = newBackends()
+	}
+	logger.Info("starting", "demo", base.Opts.Demo, "config", base.Opts.ConfigPath)
+	if err := base.LoadConfig(); err != nil {
+		configLog.Error("reading config failed", "path", base.Opts.ConfigPath, "err", err)
+	}
+	for _, c := range b.closers {
+		defer c.Close()
+	}
+	f, err := tea.LogToFile(base.LogPath("debug.log"), "dbg:")
 	if err != nil {
 		log.Fatal("rip")
 	}
 	defer f.Close()
-	prog = tea.NewProgram(initModel(), tea.WithAltScreen())
-	go i_dbus.Signals(prog)
-	go screens.UpdateRPC(prog)
-	go screens.UpdateMpay(prog)
+	prog = tea.NewProgram(initModel(b), tea.WithAltScreen())
+	go b.listen(prog)
+	go base.WatchConfig(prog)
+	go screens.UpdateRPC(prog, b.daemon)
+	go screens.UpdateMpay(prog, b.moneropay)
+	go screens.UpdateServices(prog)
+	go screens.UpdateLogs(prog)
 	if _, err := prog.Run(); err != nil {
 		log.Fatal(err)
 	}
@@ -2,65 +2,327 @@ package daemonrpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"sync/atomic"
+	"syscall"
 	"time"
 
-	"github.com/davecgh/go-spew/spew"
 	"github.com/moneronodo/sshui/internal/base"
 	"github.com/moneronodo/sshui/internal/model/daemonrpc"
 )
 
-type DaemonRequestBody struct {
-	body         []byte
-	responseType daemonrpc.DaemonRPCResponseWrapper
+var logger = base.Logger("rpc")
+
+const jsonRpcPath = "/json_rpc"
+
+type Client struct {
+	url     string
+	http    *http.Client
+	id      atomic.Uint64
+	noBatch atomic.Bool
+}
+
+func NewClient(url string) *Client {
+	return NewClientTransport(url, nil)
+}
+
+// A client whose requests go through rt instead of the network, nil for
+// the network
+func NewClientTransport(url string, rt http.RoundTripper) *Client {
+	return &Client{
+		url:  url,
+		http: &http.Client{Timeout: 3 * time.Second, Transport: rt},
+	}
+}
+
+// Carries out the calls of a batch. *Client sends them to monerod, fakes
+// can answer them with Batch.Serve.
+type Doer interface {
+	Do(ctx context.Context, b *Batch)
 }
 
-func DaemonPost(url string, body DaemonRequestBody) *daemonrpc.DaemonRPCResponse {
-	c := &http.Client{Timeout: 3 * time.Second}
-	resp, err := c.Post(url, "application/json", bytes.NewReader(body.body))
+// Calls m with params
+func Call[Req, Resp any](ctx context.Context, c Doer, m Method[Req, Resp], params Req) (*Resp, error) {
+	var b Batch
+	p := Queue(&b, m, params)
+	c.Do(ctx, &b)
+	return p.Result, p.Err
+}
+
+// JSON-RPC methods are posted to /json_rpc, methods with a Path to their
+// own endpoint.
+func send[Req, Resp any](ctx context.Context, c *Client, m Method[Req, Resp], params Req) (*Resp, error) {
+	resp := new(Resp)
+	if m.Path != "" {
+		body, err := json.Marshal(params)
+		if err != nil {
+			return resp, err
+		}
+		data, err := c.post(ctx, m.Path, body)
+		if err != nil {
+			return resp, err
+		}
+		if err := json.Unmarshal(data, resp); err != nil {
+			logger.Warn("decode failed", "path", m.Path, "err", err)
+			return resp, &daemonrpc.DaemonDecodeErr{Err: err}
+		}
+		return resp, checkStatus(data)
+	}
+	body, err := json.Marshal(c.request(m.Name, params))
 	if err != nil {
-		spew.Fprintf(base.Dump, "http: %v\n", err)
-		return &daemonrpc.DaemonRPCResponse{}
+		return resp, err
 	}
-	defer resp.Body.Close()
-	dec := json.NewDecoder(resp.Body)
-	j := daemonrpc.MakeDaemonRPCResponse(body.responseType)
-	if err := dec.Decode(j); err != nil {
-		spew.Fprintf(base.Dump, "Decode: %v\n", err)
-		return &daemonrpc.DaemonRPCResponse{}
+	data, err := c.post(ctx, jsonRpcPath, body)
+	if err != nil {
+		return resp, err
+	}
+	var r daemonrpc.DaemonRPCResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		logger.Warn("decode failed", "method", m.Name, "err", err)
+		return resp, &daemonrpc.DaemonDecodeErr{Err: err}
+	}
+	return resp, decodeResult(r, resp)
+}
+
+// Like Call, but packs the outcome into a message for the screens.
+func CallMsg[Req, Resp any](ctx context.Context, c Doer, m Method[Req, Resp], params Req) daemonrpc.DaemonRPCMsg[Resp] {
+	resp, err := Call(ctx, c, m, params)
+	return daemonrpc.DaemonRPCMsg[Resp]{
+		Result: resp,
+		Err:    err,
 	}
-	return j
 }
 
-func daemonMakeRequestBody(method string, params any) []byte {
-	req := &daemonrpc.DaemonRPCRequest{
+func (c *Client) request(method string, params any) daemonrpc.DaemonRPCRequest {
+	return daemonrpc.DaemonRPCRequest{
 		Jsonrpc: "2.0",
-		Id:      0,
+		Id:      c.id.Add(1),
 		Method:  method,
 		Params:  params,
 	}
-	b, err := json.Marshal(req)
+}
+
+func decodeResult(r daemonrpc.DaemonRPCResponse, out any) error {
+	if r.Error != nil {
+		return r.Error
+	}
+	if len(r.Result) == 0 {
+		return &daemonrpc.DaemonDecodeErr{Err: errors.New("missing result")}
+	}
+	if err := json.Unmarshal(r.Result, out); err != nil {
+		logger.Warn("decode failed", "err", err)
+		return &daemonrpc.DaemonDecodeErr{Err: err}
+	}
+	return checkStatus(r.Result)
+}
+
+func checkStatus(data []byte) error {
+	var status struct {
+		Status string `json:"status"`
+	}
+	json.Unmarshal(data, &status)
+	if status.Status != "" && status.Status != "OK" {
+		return &daemonrpc.DaemonStatusErr{Status: status.Status}
+	}
+	return nil
+}
+
+func classifyErr(err error) error {
+	var ne net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return &daemonrpc.DaemonConnectionRefusedErr{Err: err}
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &ne) && ne.Timeout():
+		return &daemonrpc.DaemonTimeoutErr{Err: err}
+	}
+	return err
+}
+
+func newPost(ctx context.Context, url string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// Posts with digest authentication when RPC credentials are configured,
+// answering a fresh challenge once if the cached nonce was rejected.
+func (c *Client) do(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	req, err := newPost(ctx, url, body)
+	if err != nil {
+		return nil, err
+	}
+	user, pass, auth := credentials()
+	if auth {
+		authorize(req, user, pass)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !auth {
+		return resp, err
+	}
+	if !updateChallenge(resp) {
+		return resp, nil
+	}
+	resp.Body.Close()
+	req, err = newPost(ctx, url, body)
+	if err != nil {
+		return nil, err
+	}
+	authorize(req, user, pass)
+	return c.http.Do(req)
+}
+
+func (c *Client) post(ctx context.Context, path string, body []byte) ([]byte, error) {
+	resp, err := c.do(ctx, c.url+path, body)
+	if err != nil {
+		logger.Warn("request failed", "path", path, "err", err)
+		return nil, classifyErr(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &daemonrpc.DaemonHTTPStatusErr{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil
+		logger.Warn("reading response failed", "path", path, "err", err)
+		return nil, classifyErr(err)
+	}
+	return data, nil
+}
+
+// A set of calls sent together with Client.Do. JSON-RPC methods go out as
+// one batch request, endpoint methods are called one after another.
+type Batch struct {
+	calls []*batchCall
+}
+
+type batchCall struct {
+	method string
+	rpc    bool
+	params any
+	run    func(ctx context.Context, c *Client)
+	set    func(r daemonrpc.DaemonRPCResponse)
+	fail   func(err error)
+}
+
+// Filled in by Client.Do
+type Pending[Resp any] struct {
+	Result *Resp
+	Err    error
+}
+
+func (p *Pending[Resp]) Msg() any {
+	return daemonrpc.DaemonRPCMsg[Resp]{
+		Result: p.Result,
+		Err:    p.Err,
 	}
-	return b
 }
 
-func DaemonRequestBodyGetInfo() DaemonRequestBody {
-	method := "get_info"
-	body := DaemonRequestBody{
-		daemonMakeRequestBody(method, nil),
-		daemonrpc.DaemonRPCResponseWrapper(&daemonrpc.DaemonResponseBodyGetInfo{}),
+func Queue[Req, Resp any](b *Batch, m Method[Req, Resp], params Req) *Pending[Resp] {
+	p := &Pending[Resp]{Result: new(Resp)}
+	call := &batchCall{
+		method: m.Name,
+		rpc:    m.Path == "",
+		params: params,
+		run: func(ctx context.Context, c *Client) {
+			p.Result, p.Err = send(ctx, c, m, params)
+		},
+		set: func(r daemonrpc.DaemonRPCResponse) {
+			p.Err = decodeResult(r, p.Result)
+		},
+		fail: func(err error) {
+			p.Err = err
+		},
+	}
+	b.calls = append(b.calls, call)
+	return p
+}
+
+// Sends the calls of b. Daemons that don't understand batch requests get
+// one request per call from then on.
+func (c *Client) Do(ctx context.Context, b *Batch) {
+	var rpc []*batchCall
+	for _, call := range b.calls {
+		if !call.rpc {
+			call.run(ctx, c)
+		} else {
+			rpc = append(rpc, call)
+		}
+	}
+	if len(rpc) == 0 {
+		return
+	}
+	if len(rpc) == 1 || c.noBatch.Load() {
+		for _, call := range rpc {
+			call.run(ctx, c)
+		}
+		return
+	}
+	reqs := make([]daemonrpc.DaemonRPCRequest, len(rpc))
+	ids := map[uint64]*batchCall{}
+	for i, call := range rpc {
+		reqs[i] = c.request(call.method, call.params)
+		ids[reqs[i].Id] = call
+	}
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		for _, call := range rpc {
+			call.fail(err)
+		}
+		return
+	}
+	data, err := c.post(ctx, jsonRpcPath, body)
+	var status *daemonrpc.DaemonHTTPStatusErr
+	if err != nil && !(errors.As(err, &status) && status.StatusCode != http.StatusUnauthorized) {
+		for _, call := range rpc {
+			call.fail(err)
+		}
+		return
+	}
+	var resps []daemonrpc.DaemonRPCResponse
+	if err == nil {
+		err = json.Unmarshal(data, &resps)
+	}
+	if err != nil {
+		logger.Info("batches unsupported, sending calls one by one", "err", err)
+		c.noBatch.Store(true)
+		for _, call := range rpc {
+			call.run(ctx, c)
+		}
+		return
+	}
+	for _, r := range resps {
+		if call, ok := ids[r.Id]; ok {
+			call.set(r)
+			delete(ids, r.Id)
+		}
+	}
+	for id, call := range ids {
+		call.fail(&daemonrpc.DaemonDecodeErr{Err: fmt.Errorf("no response for request %d", id)})
 	}
-	return body
 }
 
-func DaemonRequestBodyGetVersion() DaemonRequestBody {
-	method := "get_version"
-	body := DaemonRequestBody{
-		daemonMakeRequestBody(method, nil),
-		daemonrpc.DaemonRPCResponseWrapper(&daemonrpc.DaemonResponseBodyGetVersion{}),
+// Answers the calls of b with fn instead of a daemon. fn gets each method
+// name with its params and returns the result as monerod would.
+func (b *Batch) Serve(fn func(method string, params any) (json.RawMessage, error)) {
+	for _, call := range b.calls {
+		data, err := fn(call.method, call.params)
+		if err != nil {
+			call.fail(err)
+			continue
+		}
+		call.set(daemonrpc.DaemonRPCResponse{Result: data})
 	}
-	return body
 }
@@ -0,0 +1,299 @@
+package daemonrpc
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/model/daemonrpc"
+)
+
+var fakeResults = map[string]json.RawMessage{
+	"get_info":    json.RawMessage(`{"status":"OK","height":3300000}`),
+	"get_version": json.RawMessage(`{"status":"OK","version":196613}`),
+}
+
+// monerod behind --rpc-login, or without it when user is empty
+type fakeMonerod struct {
+	user, pass, realm string
+	// answers batch requests, old monerods don't
+	batch bool
+	// how batches are turned down, with a status or with an error object
+	batchStatus int
+	// each nonce is good for one request
+	stale bool
+
+	mu    sync.Mutex
+	nonce int
+	used  map[string]bool
+	// what each request came to: 401, batch, batch rejected or a method
+	log []string
+}
+
+func (m *fakeMonerod) record(s string) {
+	m.log = append(m.log, s)
+}
+
+// Checks the Authorization header the way monerod does
+func (m *fakeMonerod) authorized(r *http.Request) bool {
+	scheme, rest, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if scheme != "Digest" {
+		return false
+	}
+	p := parseDigestParams(rest)
+	nonce := fmt.Sprint(m.nonce)
+	if p["username"] != m.user || p["realm"] != m.realm || p["nonce"] != nonce ||
+		p["uri"] != r.URL.RequestURI() || p["qop"] != "auth" || m.used[p["nc"]+p["cnonce"]] {
+		return false
+	}
+	ha1 := digestHex(md5.New, m.user, m.realm, m.pass)
+	ha2 := digestHex(md5.New, r.Method, r.URL.RequestURI())
+	if p["response"] != digestHex(md5.New, ha1, nonce, p["nc"], p["cnonce"], "auth", ha2) {
+		return false
+	}
+	m.used[p["nc"]+p["cnonce"]] = true
+	if m.stale {
+		m.nonce++
+	}
+	return true
+}
+
+func (m *fakeMonerod) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.user != "" && !m.authorized(r) {
+		m.record("401")
+		w.Header().Add("WWW-Authenticate", fmt.Sprintf(`Digest qop="auth", algorithm=MD5, realm=%s, nonce="%d"`,
+			quote(m.realm), m.nonce))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	if strings.HasPrefix(string(body), "[") {
+		if !m.batch {
+			m.record("batch rejected")
+			if m.batchStatus != 0 {
+				w.WriteHeader(m.batchStatus)
+				return
+			}
+			w.Write([]byte(`{"jsonrpc":"2.0","id":0,"error":{"code":-32600,"message":"Invalid Request"}}`))
+			return
+		}
+		m.record("batch")
+		var reqs []daemonrpc.DaemonRPCRequest
+		json.Unmarshal(body, &reqs)
+		var resps []daemonrpc.DaemonRPCResponse
+		for _, req := range reqs {
+			resps = append(resps, daemonrpc.DaemonRPCResponse{Jsonrpc: "2.0", Id: req.Id, Result: fakeResults[req.Method]})
+		}
+		json.NewEncoder(w).Encode(resps)
+		return
+	}
+	var req daemonrpc.DaemonRPCRequest
+	json.Unmarshal(body, &req)
+	m.record(req.Method)
+	json.NewEncoder(w).Encode(daemonrpc.DaemonRPCResponse{Jsonrpc: "2.0", Id: req.Id, Result: fakeResults[req.Method]})
+}
+
+// Makes user and pass the saved RPC login, no login when user is empty
+func setLogin(t *testing.T, user, pass string) {
+	t.Helper()
+	enabled := "FALSE"
+	if user != "" {
+		enabled = "TRUE"
+	}
+	c, err := json.Marshal(map[string]any{"config": map[string]any{
+		"rpc_enabled": enabled, "rpcu": user, "rpcp": pass,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, c, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := base.Opts
+	t.Cleanup(func() { base.Opts = opts })
+	base.Opts.ConfigPath = path
+	if err := base.LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientDo(t *testing.T) {
+	tests := []struct {
+		name   string
+		server *fakeMonerod
+		// the login sshui has, when it differs from monerod's
+		pass string
+		// what monerod sees of two rounds of get_info and get_version
+		want   []string
+		failed bool
+	}{
+		{
+			name:   "no login",
+			server: &fakeMonerod{batch: true},
+			want:   []string{"batch", "batch"},
+		},
+		{
+			name:   "digest",
+			server: &fakeMonerod{user: "nodo", pass: "hunter2", realm: "monero-rpc", batch: true},
+			// the challenge is answered up front the second time
+			want: []string{"401", "batch", "batch"},
+		},
+		{
+			name:   "quotes in the login",
+			server: &fakeMonerod{user: `no"do\`, pass: `hun"ter\2`, realm: `monero "rpc"`, batch: true},
+			want:   []string{"401", "batch", "batch"},
+		},
+		{
+			name:   "stale nonce",
+			server: &fakeMonerod{user: "nodo", pass: "hunter2", realm: "monero-rpc", batch: true, stale: true},
+			want:   []string{"401", "batch", "401", "batch"},
+		},
+		{
+			name:   "wrong password",
+			server: &fakeMonerod{user: "nodo", pass: "hunter2", realm: "monero-rpc", batch: true},
+			pass:   "hunter3",
+			// one fresh challenge per request, then it gives up
+			want:   []string{"401", "401", "401", "401"},
+			failed: true,
+		},
+		{
+			name:   "batch rejected",
+			server: &fakeMonerod{},
+			want:   []string{"batch rejected", "get_info", "get_version", "get_info", "get_version"},
+		},
+		{
+			name:   "batch rejected with a status",
+			server: &fakeMonerod{batchStatus: http.StatusInternalServerError},
+			want:   []string{"batch rejected", "get_info", "get_version", "get_info", "get_version"},
+		},
+		{
+			name:   "batch rejected behind digest",
+			server: &fakeMonerod{user: "nodo", pass: "hunter2", realm: "monero-rpc"},
+			want:   []string{"401", "batch rejected", "get_info", "get_version", "get_info", "get_version"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			challenge = nil
+			m := tc.server
+			m.used = map[string]bool{}
+			pass := m.pass
+			if tc.pass != "" {
+				pass = tc.pass
+			}
+			setLogin(t, m.user, pass)
+			srv := httptest.NewServer(m)
+			defer srv.Close()
+
+			c := NewClient(srv.URL)
+			for round := range 2 {
+				var b Batch
+				info := Queue(&b, GetInfo, daemonrpc.NoParams{})
+				version := Queue(&b, GetVersion, daemonrpc.NoParams{})
+				c.Do(context.Background(), &b)
+				if tc.failed {
+					var status *daemonrpc.DaemonHTTPStatusErr
+					if !errors.As(info.Err, &status) || status.StatusCode != http.StatusUnauthorized {
+						t.Errorf("round %d: get_info: %v, want a 401", round, info.Err)
+					}
+					continue
+				}
+				if info.Err != nil || version.Err != nil {
+					t.Errorf("round %d: %v, %v", round, info.Err, version.Err)
+				} else if info.Result.Height != 3300000 || version.Result.Version != 196613 {
+					t.Errorf("round %d: height %d, version %d", round, info.Result.Height, version.Result.Version)
+				}
+			}
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if !slices.Equal(m.log, tc.want) {
+				t.Errorf("monerod saw %q, want %q", m.log, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		want    *digestChallenge
+	}{
+		{
+			name:    "monerod",
+			headers: []string{`Digest qop="auth", algorithm=MD5, realm="monero-rpc", nonce="Vt2QZ0F+Qd6iU7E7w9+y0A=="`},
+			want:    &digestChallenge{realm: "monero-rpc", nonce: "Vt2QZ0F+Qd6iU7E7w9+y0A==", algorithm: "MD5", qop: "auth"},
+		},
+		{
+			name:    "escapes and commas",
+			headers: []string{`Digest realm="a \"b\", c\\", nonce="n", opaque="o,p"`},
+			want:    &digestChallenge{realm: `a "b", c\`, nonce: "n", opaque: "o,p"},
+		},
+		{
+			name: "first one we can answer",
+			headers: []string{
+				`Basic realm="monero-rpc"`,
+				`Digest realm="r", nonce="n1", algorithm=SHA-512-256`,
+				`Digest realm="r", nonce="n2", qop="auth-int"`,
+				`Digest realm="r", nonce="n3", qop="auth-int,auth", algorithm=SHA-256-sess`,
+			},
+			want: &digestChallenge{realm: "r", nonce: "n3", algorithm: "SHA-256-sess", qop: "auth"},
+		},
+		{
+			name:    "no nonce",
+			headers: []string{`Digest realm="r"`},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseChallenge(tc.headers)
+			if got == nil || tc.want == nil {
+				if got != tc.want {
+					t.Errorf("got %+v, want %+v", got, tc.want)
+				}
+				return
+			}
+			if *got != *tc.want {
+				t.Errorf("got %+v, want %+v", *got, *tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizationQuotes(t *testing.T) {
+	dc := &digestChallenge{realm: `monero "rpc"`, nonce: `n\1`, opaque: `"o"`, qop: "auth"}
+	header := dc.authorization("POST", `/json_rpc?a="b"`, `no"do\`, "hunter2")
+	scheme, rest, _ := strings.Cut(header, " ")
+	if scheme != "Digest" {
+		t.Fatalf("scheme %q", scheme)
+	}
+	p := parseDigestParams(rest)
+	for k, want := range map[string]string{
+		"username": `no"do\`,
+		"realm":    `monero "rpc"`,
+		"nonce":    `n\1`,
+		"uri":      `/json_rpc?a="b"`,
+		"opaque":   `"o"`,
+		"qop":      "auth",
+		"nc":       "00000001",
+	} {
+		if p[k] != want {
+			t.Errorf("%s: %q, want %q in %s", k, p[k], want, header)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package daemonrpc
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+// HTTP digest authentication (RFC 7616) as used by monerod's --rpc-login.
+// The last challenge is kept so later requests can authenticate up front
+// instead of taking a 401 round trip every time.
+
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	nc        uint32
+}
+
+var (
+	digestMu  sync.Mutex
+	challenge *digestChallenge
+)
+
+// The login monerod runs with, from the saved config
+func credentials() (user, pass string, ok bool) {
+	l := base.SavedRPCLogin()
+	if !l.Enabled {
+		return "", "", false
+	}
+	return l.User, l.Pass, l.User != ""
+}
+
+func digestHash(algorithm string) func() hash.Hash {
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "", "MD5":
+		return md5.New
+	case "SHA-256":
+		return sha256.New
+	}
+	return nil
+}
+
+func digestHex(h func() hash.Hash, parts ...string) string {
+	d := h()
+	d.Write([]byte(strings.Join(parts, ":")))
+	return hex.EncodeToString(d.Sum(nil))
+}
+
+// Splits the parameters of a challenge, honouring commas in quoted strings.
+func parseDigestParams(s string) map[string]string {
+	params := map[string]string{}
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ,")
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = s[eq+1:]
+		var val string
+		if strings.HasPrefix(s, `"`) {
+			val, s = unquote(s[1:])
+		} else if comma := strings.IndexByte(s, ','); comma >= 0 {
+			val, s = s[:comma], s[comma:]
+		} else {
+			val, s = s, ""
+		}
+		params[key] = strings.TrimSpace(val)
+	}
+	return params
+}
+
+// Reads a quoted string up to its closing quote, s starting after the
+// opening one. Returns the value without the escapes and what follows.
+func unquote(s string) (val, rest string) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+			}
+			sb.WriteByte(s[i])
+		case '"':
+			return sb.String(), s[i+1:]
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String(), ""
+}
+
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// A quoted string for a header parameter (RFC 7616 section 3.4). The
+// username comes from the config and may hold anything.
+func quote(s string) string {
+	return `"` + quoteEscaper.Replace(s) + `"`
+}
+
+// Picks the first digest challenge with an algorithm and qop we support.
+func parseChallenge(headers []string) *digestChallenge {
+	for _, h := range headers {
+		scheme, rest, _ := strings.Cut(h, " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+		p := parseDigestParams(rest)
+		if p["nonce"] == "" || digestHash(p["algorithm"]) == nil {
+			continue
+		}
+		qop := ""
+		if p["qop"] != "" {
+			for q := range strings.SplitSeq(p["qop"], ",") {
+				if strings.TrimSpace(q) == "auth" {
+					qop = "auth"
+				}
+			}
+			if qop == "" {
+				continue
+			}
+		}
+		return &digestChallenge{
+			realm:     p["realm"],
+			nonce:     p["nonce"],
+			opaque:    p["opaque"],
+			algorithm: p["algorithm"],
+			qop:       qop,
+		}
+	}
+	return nil
+}
+
+func (dc *digestChallenge) authorization(method, uri, user, pass string) string {
+	h := digestHash(dc.algorithm)
+	dc.nc++
+	nc := fmt.Sprintf("%08x", dc.nc)
+	cnonce := make([]byte, 16)
+	rand.Read(cnonce)
+	cn := hex.EncodeToString(cnonce)
+
+	ha1 := digestHex(h, user, dc.realm, pass)
+	if strings.HasSuffix(strings.ToUpper(dc.algorithm), "-SESS") {
+		ha1 = digestHex(h, ha1, dc.nonce, cn)
+	}
+	ha2 := digestHex(h, method, uri)
+	var response string
+	if dc.qop == "" {
+		response = digestHex(h, ha1, dc.nonce, ha2)
+	} else {
+		response = digestHex(h, ha1, dc.nonce, nc, cn, dc.qop, ha2)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `Digest username=%s, realm=%s, nonce=%s, uri=%s, response="%s"`,
+		quote(user), quote(dc.realm), quote(dc.nonce), quote(uri), response)
+	if dc.algorithm != "" {
+		fmt.Fprintf(&sb, ", algorithm=%s", dc.algorithm)
+	}
+	if dc.qop != "" {
+		fmt.Fprintf(&sb, `, qop=%s, nc=%s, cnonce="%s"`, dc.qop, nc, cn)
+	}
+	if dc.opaque != "" {
+		fmt.Fprintf(&sb, ", opaque=%s", quote(dc.opaque))
+	}
+	return sb.String()
+}
+
+func authorize(req *http.Request, user, pass string) {
+	digestMu.Lock()
+	defer digestMu.Unlock()
+	if challenge == nil {
+		return
+	}
+	req.Header.Set("Authorization",
+		challenge.authorization(req.Method, req.URL.RequestURI(), user, pass))
+}
+
+// Stores the challenge of a 401 response, returns false if there is none
+// we can answer.
+func updateChallenge(resp *http.Response) bool {
+	dc := parseChallenge(resp.Header.Values("WWW-Authenticate"))
+	digestMu.Lock()
+	defer digestMu.Unlock()
+	challenge = dc
+	return dc != nil
+}
@@ -0,0 +1,60 @@
+package daemonrpc
+
+import "github.com/moneronodo/sshui/internal/model/daemonrpc"
+
+// A monerod method with its request and response types. Methods with a
+// Path are served on their own endpoint instead of /json_rpc.
+type Method[Req, Resp any] struct {
+	Name string
+	Path string
+}
+
+var (
+	GetInfo = Method[daemonrpc.NoParams, daemonrpc.DaemonResponseBodyGetInfo]{
+		Name: "get_info",
+	}
+	GetVersion = Method[daemonrpc.NoParams, daemonrpc.DaemonResponseBodyGetVersion]{
+		Name: "get_version",
+	}
+	GetConnections = Method[daemonrpc.NoParams, daemonrpc.DaemonResponseBodyGetConnections]{
+		Name: "get_connections",
+	}
+	GetBans = Method[daemonrpc.NoParams, daemonrpc.DaemonResponseBodyGetBans]{
+		Name: "get_bans",
+	}
+	SetBans = Method[daemonrpc.DaemonRequestParamsSetBans, daemonrpc.DaemonResponseBodySetBans]{
+		Name: "set_bans",
+	}
+	GetLastBlockHeader = Method[daemonrpc.NoParams, daemonrpc.DaemonResponseBodyGetLastBlockHeader]{
+		Name: "get_last_block_header",
+	}
+	GetBlockHeadersRange = Method[daemonrpc.DaemonRequestParamsGetBlockHeadersRange, daemonrpc.DaemonResponseBodyGetBlockHeadersRange]{
+		Name: "get_block_headers_range",
+	}
+	GetBlockHeaderByHeight = Method[daemonrpc.DaemonRequestParamsGetBlockHeaderByHeight, daemonrpc.DaemonResponseBodyGetBlockHeader]{
+		Name: "get_block_header_by_height",
+	}
+	GetBlockHeaderByHash = Method[daemonrpc.DaemonRequestParamsGetBlockHeaderByHash, daemonrpc.DaemonResponseBodyGetBlockHeader]{
+		Name: "get_block_header_by_hash",
+	}
+	FlushTxpool = Method[daemonrpc.DaemonRequestParamsFlushTxpool, daemonrpc.DaemonResponseBodyFlushTxpool]{
+		Name: "flush_txpool",
+	}
+
+	GetTransactionPool = Method[daemonrpc.NoParams, daemonrpc.DaemonResponseBodyGetTransactionPool]{
+		Name: "get_transaction_pool",
+		Path: "/get_transaction_pool",
+	}
+	GetTransactionPoolStats = Method[daemonrpc.NoParams, daemonrpc.DaemonResponseBodyGetTransactionPoolStats]{
+		Name: "get_transaction_pool_stats",
+		Path: "/get_transaction_pool_stats",
+	}
+	GetPeerList = Method[daemonrpc.DaemonRequestParamsGetPeerList, daemonrpc.DaemonResponseBodyGetPeerList]{
+		Name: "get_peer_list",
+		Path: "/get_peer_list",
+	}
+	GetNetStats = Method[daemonrpc.NoParams, daemonrpc.DaemonResponseBodyGetNetStats]{
+		Name: "get_net_stats",
+		Path: "/get_net_stats",
+	}
+)
@@ -0,0 +1,148 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	dbus "github.com/godbus/dbus/v5"
+	"github.com/moneronodo/sshui/internal/backend/i_dbus"
+)
+
+const (
+	busInterface = "com.moneronodo.embeddedInterface"
+	// how often the Nodo service reports hardware and service status
+	busStatusRate = 5 * time.Second
+)
+
+// The Nodo embedded interface: periodic status reports and answers to
+// the calls sshui makes.
+type bus struct {
+	start   time.Time
+	signals chan *dbus.Signal
+}
+
+func newBus() *bus {
+	b := &bus{
+		start:   time.Now(),
+		signals: make(chan *dbus.Signal, 10),
+	}
+	go b.report()
+	return b
+}
+
+// Passes the signals to prog, the bus never goes away
+func (b *bus) Listen(prog *tea.Program) {
+	i_dbus.Forward(prog, b.signals)
+}
+
+func (b *bus) emit(name string, body ...any) {
+	b.signals <- &dbus.Signal{
+		Sender: "com.monero.nodo",
+		Path:   "/com/monero/nodo",
+		Name:   busInterface + "." + name,
+		Body:   body,
+	}
+}
+
+// Lines of CPU load (%), clock (GHz), RAM used and total (GB), temperature
+// (°C), blockchain SSD used and total (TB), eMMC used and total (GB) and
+// uptime.
+func (b *bus) hardwareStatus() string {
+	now := time.Now()
+	t := now.Unix() / int64(busStatusRate.Seconds())
+	load := 8 + float64(number("load", t)%55)
+	up := now.Sub(b.start.Add(-(3*24*time.Hour + 4*time.Hour + 17*time.Minute)))
+	return strings.Join([]string{
+		fmt.Sprintf("%.1f", load),
+		fmt.Sprintf("%.1f", 1.4+load/100*0.8),
+		fmt.Sprintf("%.2f", 2.9+float64(number("ram", t)%60)/100),
+		"7.76",
+		fmt.Sprintf("%.1f", 44+load/4+float64(number("temp", t)%20)/10),
+		fmt.Sprintf("%.3f", 0.231+now.Sub(b.start).Hours()/1_000),
+		"3.638",
+		"9.4",
+		"58.2",
+		fmt.Sprintf("%d days, %d:%02d", int(up.Hours())/24, int(up.Hours())%24, int(up.Minutes())%60),
+	}, "\n")
+}
+
+func (b *bus) serviceStatus() string {
+	return strings.Join([]string{
+		"monerod:active",
+		"tor:active",
+		"i2pd:inactive",
+		"monero-lws:active",
+		"sshd:active",
+		"moneropay:active",
+	}, "\n")
+}
+
+func (b *bus) report() {
+	tick := time.NewTicker(busStatusRate)
+	defer tick.Stop()
+	for {
+		b.emit("hardwareStatusReadyNotification", b.hardwareStatus())
+		b.emit("serviceStatusReadyNotification", b.serviceStatus())
+		<-tick.C
+	}
+}
+
+func (b *bus) Call(ctx context.Context, method string, args ...any) ([]any, error) {
+	switch method {
+	case "restart", "shutdown":
+		go b.emit("serviceManagerNotification", "Demo mode: "+method+" skipped")
+	case "startRecovery":
+		fs, _ := args[0].(bool)
+		resync, _ := args[1].(bool)
+		go b.recover(fs, resync)
+	case "factoryReset":
+		if pw, _ := args[0].(string); pw == "" {
+			return nil, fmt.Errorf("wrong password")
+		}
+		go func() {
+			time.Sleep(time.Second)
+			b.emit("factoryResetStarted")
+			time.Sleep(8 * time.Second)
+			b.emit("factoryResetCompleted")
+		}()
+	case "setPassword":
+		go func() {
+			time.Sleep(time.Second)
+			b.emit("passwordChangeStatus", 0)
+		}()
+	default:
+		return nil, fmt.Errorf("demo: unknown method %s", method)
+	}
+	return nil, nil
+}
+
+// Goes through the steps of a recovery in well under a minute
+func (b *bus) recover(fs, resync bool) {
+	step := func(format string, a ...any) {
+		time.Sleep(2 * time.Second)
+		b.emit("startRecoveryNotification", fmt.Sprintf(format, a...))
+	}
+	step("Recovery started")
+	if fs {
+		step("Checking filesystem")
+		for p := 25; p < 100; p += 25 {
+			step("Filesystem check %d%%", p)
+		}
+		step("Filesystem check complete")
+	}
+	if resync {
+		b.emit("serviceManagerNotification", "Stopping monerod")
+		step("Purging blockchain")
+		step("Purging blockchain 60%%")
+		step("Blockchain purge complete")
+		for p := 0; p < 100; p += 20 {
+			step("Resync %.1f%%", float64(p))
+		}
+		step("Resync complete")
+		b.emit("serviceManagerNotification", "Starting monerod")
+	}
+	step("Recovery complete")
+}
@@ -0,0 +1,488 @@
+package demo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	rpc_model "github.com/moneronodo/sshui/internal/model/daemonrpc"
+)
+
+const (
+	// network height when the demo starts
+	chainTop = 3_480_000
+	// blocks the node still has to sync
+	chainBehind = 1_500
+	// blocks per second while syncing
+	chainSyncRate = 20
+	// sped up from the real two minutes so there is something to watch
+	chainBlockTime = 30 * time.Second
+
+	chainVersion   = "0.18.4.2-release"
+	poolMax        = 150
+	poolRate       = 4 * time.Second
+	peersOut       = 12
+	peersIn        = 5
+	baseReward     = 600_000_000_000
+	baseDifficulty = 480_000_000_000
+)
+
+// A monerod that syncs up to the network, then follows it
+type chain struct {
+	mu       sync.Mutex
+	start    time.Time
+	lastTick time.Time
+	lastTop  uint64
+	txSeq    uint64
+	pool     []rpc_model.TxPoolTransaction
+	bans     map[string]time.Time
+}
+
+func newChain() *chain {
+	now := time.Now()
+	c := &chain{
+		start:    now,
+		lastTick: now,
+		bans: map[string]time.Time{
+			"91.198.115.0/24": now.Add(72 * time.Hour),
+			"185.237.12.47":   now.Add(5 * time.Hour),
+		},
+	}
+	c.lastTop = c.height()
+	for range 18 {
+		c.addTx(now.Add(-time.Duration(number("seed", c.txSeq)%600) * time.Second))
+	}
+	return c
+}
+
+func (c *chain) networkHeight() uint64 {
+	return c.heightAt(time.Now())
+}
+
+// Height of the local chain, the number of blocks not its top block
+func (c *chain) height() uint64 {
+	synced := chainTop - chainBehind + uint64(time.Since(c.start).Seconds()*chainSyncRate)
+	return min(synced, c.networkHeight())
+}
+
+func (c *chain) synchronized() bool {
+	return c.height() == c.networkHeight()
+}
+
+// Network height at time t
+func (c *chain) heightAt(t time.Time) uint64 {
+	if t.Before(c.start) {
+		return chainTop - uint64(c.start.Sub(t)/(2*time.Minute))
+	}
+	return chainTop + uint64(t.Sub(c.start)/chainBlockTime)
+}
+
+func blockTime(c *chain, h uint64) time.Time {
+	if h <= chainTop {
+		return c.start.Add(-time.Duration(chainTop-h) * 2 * time.Minute)
+	}
+	return c.start.Add(time.Duration(h-chainTop) * chainBlockTime)
+}
+
+func (c *chain) header(h uint64) rpc_model.BlockHeader {
+	txs := number("txs", h) % 40
+	size := 300 + txs*(1_400+number("size", h)%1_200)
+	diff := baseDifficulty + number("diff", h)%40_000_000_000
+	return rpc_model.BlockHeader{
+		BlockSize:            size,
+		BlockWeight:          size + txs*120,
+		CumulativeDifficulty: 400_000_000_000_000_000 + h*baseDifficulty,
+		Depth:                c.height() - 1 - h,
+		Difficulty:           diff,
+		Hash:                 hash("block", h),
+		Height:               h,
+		LongTermWeight:       size,
+		MajorVersion:         16,
+		MinerTxHash:          hash("miner", h),
+		MinorVersion:         16,
+		Nonce:                uint32(number("nonce", h)),
+		NumTxes:              uint(txs),
+		PrevHash:             hash("block", h-1),
+		Reward:               baseReward + txs*(number("fee", h)%60_000_000),
+		Timestamp:            blockTime(c, h).Unix(),
+		WideDifficulty:       fmt.Sprintf("0x%x", diff),
+	}
+}
+
+func (c *chain) addTx(received time.Time) {
+	c.txSeq++
+	weight := 1_500 + number("weight", c.txSeq)%4_000
+	fee := weight * (20_000 + number("rate", c.txSeq)%300_000)
+	c.pool = append(c.pool, rpc_model.TxPoolTransaction{
+		BlobSize:        weight - number("blob", c.txSeq)%200,
+		DoubleSpendSeen: number("ds", c.txSeq)%97 == 0,
+		Fee:             fee,
+		IdHash:          hash("tx", c.txSeq),
+		LastRelayedTime: received.Unix(),
+		ReceiveTime:     received.Unix(),
+		Relayed:         number("relay", c.txSeq)%13 != 0,
+		Weight:          weight,
+	})
+}
+
+// Brings the pool up to date: new blocks take most of it, new transactions
+// trickle in.
+func (c *chain) tick() {
+	now := time.Now()
+	if top := c.height(); top != c.lastTop {
+		mined := blockTime(c, top)
+		c.pool = slices.DeleteFunc(c.pool, func(tx rpc_model.TxPoolTransaction) bool {
+			return time.Unix(tx.ReceiveTime, 0).Before(mined) && number("keep", tx.IdHash)%5 != 0
+		})
+		c.lastTop = top
+	}
+	for t := c.lastTick.Add(poolRate); t.Before(now); t = t.Add(poolRate) {
+		if len(c.pool) < poolMax && number("arrive", t.Unix())%3 != 0 {
+			c.addTx(t)
+		}
+		c.lastTick = t
+	}
+}
+
+func (c *chain) info() rpc_model.DaemonResponseBodyGetInfo {
+	h, target := c.height(), c.networkHeight()
+	top := hash("block", h-1)
+	return rpc_model.DaemonResponseBodyGetInfo{
+		AdjustedTime:             uint32(time.Now().Unix()),
+		BlockSizeLimit:           600_000,
+		BlockSizeMedian:          300_000,
+		BlockWeightLimit:         600_000,
+		BlockWeightMedian:        300_000,
+		BusySyncing:              !c.synchronized(),
+		CumulativeDifficulty:     400_000_000_000_000_000 + h*baseDifficulty,
+		DatabaseSize:             230 << 30,
+		Difficulty:               baseDifficulty,
+		FreeSpace:                1_500 << 30,
+		GreyPeerlistSize:         4_812,
+		Height:                   int(h),
+		HeightWithoutBootstrap:   int(h),
+		IncomingConnectionsCount: peersIn,
+		Mainnet:                  true,
+		Nettype:                  "mainnet",
+		OutgoingConnectionsCount: peersOut,
+		RpcConnectionsCount:      2,
+		StartTime:                int(c.start.Add(-26 * time.Hour).Unix()),
+		Status:                   "OK",
+		Synchronized:             c.synchronized(),
+		Target:                   120,
+		TargetHeight:             int(target),
+		TopBlockHash:             top,
+		TopHash:                  top,
+		TxCount:                  int(45_000_000 + h*12),
+		TxPoolSize:               len(c.pool),
+		Version:                  chainVersion,
+		WhitePeerlistSize:        1_000,
+		WideCumulativeDifficulty: fmt.Sprintf("0x%x", 400_000_000_000_000_000+h*baseDifficulty),
+		WideDifficulty:           fmt.Sprintf("0x%x", baseDifficulty),
+	}
+}
+
+func (c *chain) connections() []rpc_model.GetConnectionsConnection {
+	var conns []rpc_model.GetConnectionsConnection
+	for i := range peersOut + peersIn {
+		in := i >= peersOut
+		live := uint64(time.Since(c.start).Seconds()) + number("live", i)%20_000
+		state := "normal"
+		height := c.networkHeight()
+		if !c.synchronized() && !in {
+			state = "synchronizing"
+		}
+		if number("behind", i)%4 == 0 {
+			height -= number("lag", i) % 3
+		}
+		ip := fmt.Sprintf("%d.%d.%d.%d", 20+number("a", i)%200, number("b", i)%256,
+			number("c", i)%256, 1+number("d", i)%254)
+		port := "18080"
+		if in {
+			port = fmt.Sprint(30_000 + number("port", i)%30_000)
+		}
+		conns = append(conns, rpc_model.GetConnectionsConnection{
+			Address:         ip + ":" + port,
+			AvgDownload:     number("avgdl", i) % 40,
+			AvgUpload:       number("avgul", i) % 30,
+			ConnectionId:    hash("conn", i)[:32],
+			CurrentDownload: number("dl", i, live/10) % 60,
+			CurrentUpload:   number("ul", i, live/10) % 45,
+			Height:          height,
+			Host:            ip,
+			Incoming:        in,
+			Ip:              ip,
+			LiveTime:        live,
+			PeerId:          hash("peer", i)[:16],
+			Port:            port,
+			RecvCount:       live * (2_000 + number("recv", i)%9_000),
+			SendCount:       live * (1_000 + number("send", i)%7_000),
+			State:           state,
+			SupportFlags:    1,
+		})
+	}
+	return conns
+}
+
+func (c *chain) getBans() []rpc_model.GetBansBan {
+	var bans []rpc_model.GetBansBan
+	for host, until := range c.bans {
+		left := time.Until(until)
+		if left <= 0 {
+			delete(c.bans, host)
+			continue
+		}
+		bans = append(bans, rpc_model.GetBansBan{
+			Host:    host,
+			Seconds: uint32(left.Seconds()),
+		})
+	}
+	slices.SortFunc(bans, func(a, b rpc_model.GetBansBan) int {
+		return int(b.Seconds) - int(a.Seconds)
+	})
+	return bans
+}
+
+func (c *chain) setBans(p rpc_model.DaemonRequestParamsSetBans) string {
+	for _, b := range p.Bans {
+		_, _, cidrErr := net.ParseCIDR(b.Host)
+		if net.ParseIP(b.Host) == nil && cidrErr != nil {
+			return "Failed"
+		}
+	}
+	for _, b := range p.Bans {
+		if b.Ban {
+			c.bans[b.Host] = time.Now().Add(time.Duration(b.Seconds) * time.Second)
+		} else {
+			delete(c.bans, b.Host)
+		}
+	}
+	return "OK"
+}
+
+func (c *chain) poolStats() rpc_model.TxPoolStats {
+	var s rpc_model.TxPoolStats
+	for i, tx := range c.pool {
+		size := uint32(tx.BlobSize)
+		if i == 0 || size < s.BytesMin {
+			s.BytesMin = size
+		}
+		s.BytesMax = max(s.BytesMax, size)
+		s.BytesTotal += tx.BlobSize
+		s.FeeTotal += tx.Fee
+		s.TxsTotal++
+		if time.Since(time.Unix(tx.ReceiveTime, 0)) > 10*time.Minute {
+			s.Num10m++
+		}
+		if tx.DoubleSpendSeen {
+			s.NumDoubleSpends++
+		}
+		if !tx.Relayed {
+			s.NumNotRelayed++
+		}
+		if s.Oldest == 0 || tx.ReceiveTime < s.Oldest {
+			s.Oldest = tx.ReceiveTime
+		}
+	}
+	if s.TxsTotal > 0 {
+		s.BytesMed = uint32(s.BytesTotal / uint64(s.TxsTotal))
+	}
+	return s
+}
+
+func (c *chain) flush(txids []string) {
+	if len(txids) == 0 {
+		c.pool = nil
+		return
+	}
+	c.pool = slices.DeleteFunc(c.pool, func(tx rpc_model.TxPoolTransaction) bool {
+		return slices.Contains(txids, tx.IdHash)
+	})
+}
+
+func (c *chain) peerList() rpc_model.DaemonResponseBodyGetPeerList {
+	var r rpc_model.DaemonResponseBodyGetPeerList
+	for _, conn := range c.connections() {
+		r.WhiteList = append(r.WhiteList, rpc_model.PeerListPeer{
+			Host:     conn.Host,
+			Id:       number("peer", conn.PeerId),
+			LastSeen: time.Now().Unix(),
+			Port:     18080,
+		})
+	}
+	r.Status = "OK"
+	return r
+}
+
+func (c *chain) netStats() rpc_model.DaemonResponseBodyGetNetStats {
+	r := rpc_model.DaemonResponseBodyGetNetStats{
+		StartTime: c.start.Add(-26 * time.Hour).Unix(),
+		Status:    "OK",
+	}
+	for _, conn := range c.connections() {
+		r.TotalBytesIn += conn.RecvCount
+		r.TotalBytesOut += conn.SendCount
+	}
+	r.TotalPacketsIn = r.TotalBytesIn / 1_200
+	r.TotalPacketsOut = r.TotalBytesOut / 1_200
+	return r
+}
+
+type rpcRequest struct {
+	Id     uint64          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+func params[T any](raw json.RawMessage) (T, *rpc_model.DaemonRPCErr) {
+	var p T
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return p, &rpc_model.DaemonRPCErr{Code: -32602, Message: "Invalid params"}
+		}
+	}
+	return p, nil
+}
+
+func (c *chain) call(method string, raw json.RawMessage) (any, *rpc_model.DaemonRPCErr) {
+	switch method {
+	case "get_info":
+		return c.info(), nil
+	case "get_version":
+		return rpc_model.DaemonResponseBodyGetVersion{
+			CurrentHeight: uint32(c.height()),
+			HardForks: []rpc_model.GetVersionHardForks{
+				{Height: 2_688_888, HfVersion: 15},
+				{Height: 2_689_608, HfVersion: 16},
+			},
+			Release: true,
+			Status:  "OK",
+			Version: 3<<16 | 13,
+		}, nil
+	case "get_connections":
+		return rpc_model.DaemonResponseBodyGetConnections{
+			Connections: c.connections(),
+			Status:      "OK",
+		}, nil
+	case "get_bans":
+		return rpc_model.DaemonResponseBodyGetBans{Bans: c.getBans(), Status: "OK"}, nil
+	case "set_bans":
+		p, err := params[rpc_model.DaemonRequestParamsSetBans](raw)
+		if err != nil {
+			return nil, err
+		}
+		return rpc_model.DaemonResponseBodySetBans{Status: c.setBans(p)}, nil
+	case "get_last_block_header":
+		return rpc_model.DaemonResponseBodyGetLastBlockHeader{
+			BlockHeader: c.header(c.height() - 1),
+			Status:      "OK",
+		}, nil
+	case "get_block_headers_range":
+		p, err := params[rpc_model.DaemonRequestParamsGetBlockHeadersRange](raw)
+		if err != nil {
+			return nil, err
+		}
+		if p.StartHeight > p.EndHeight || p.EndHeight >= c.height() {
+			return nil, &rpc_model.DaemonRPCErr{Code: -2, Message: "Invalid start/end heights."}
+		}
+		r := rpc_model.DaemonResponseBodyGetBlockHeadersRange{Status: "OK"}
+		for h := p.StartHeight; h <= p.EndHeight; h++ {
+			r.Headers = append(r.Headers, c.header(h))
+		}
+		return r, nil
+	case "get_block_header_by_height":
+		p, err := params[rpc_model.DaemonRequestParamsGetBlockHeaderByHeight](raw)
+		if err != nil {
+			return nil, err
+		}
+		if p.Height >= c.height() {
+			return nil, &rpc_model.DaemonRPCErr{
+				Code:    -2,
+				Message: fmt.Sprintf("Requested block height: %d greater than current top block height: %d", p.Height, c.height()-1),
+			}
+		}
+		return rpc_model.DaemonResponseBodyGetBlockHeader{BlockHeader: c.header(p.Height), Status: "OK"}, nil
+	case "get_block_header_by_hash":
+		p, err := params[rpc_model.DaemonRequestParamsGetBlockHeaderByHash](raw)
+		if err != nil {
+			return nil, err
+		}
+		// only recent blocks can be found by hash
+		top := c.height() - 1
+		for h := top; h+1_000 > top && h > 0; h-- {
+			if hash("block", h) == p.Hash {
+				return rpc_model.DaemonResponseBodyGetBlockHeader{BlockHeader: c.header(h), Status: "OK"}, nil
+			}
+		}
+		return nil, &rpc_model.DaemonRPCErr{Code: -5, Message: "Internal error: can't get block by hash. Hash = " + p.Hash + "."}
+	case "flush_txpool":
+		p, err := params[rpc_model.DaemonRequestParamsFlushTxpool](raw)
+		if err != nil {
+			return nil, err
+		}
+		c.flush(p.Txids)
+		return rpc_model.DaemonResponseBodyFlushTxpool{Status: "OK"}, nil
+	}
+	return nil, &rpc_model.DaemonRPCErr{Code: -32601, Message: "Method not found"}
+}
+
+func (c *chain) jsonRpc(req rpcRequest) rpc_model.DaemonRPCResponse {
+	r := rpc_model.DaemonRPCResponse{Jsonrpc: "2.0", Id: req.Id}
+	result, err := c.call(req.Method, req.Params)
+	if err != nil {
+		r.Error = err
+		return r
+	}
+	r.Result, _ = json.Marshal(result)
+	return r
+}
+
+func (c *chain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tick()
+
+	var resp any
+	switch r.URL.Path {
+	case "/json_rpc":
+		var batch []rpcRequest
+		var req rpcRequest
+		if json.Unmarshal(body, &batch) == nil {
+			var rs []rpc_model.DaemonRPCResponse
+			for _, req := range batch {
+				rs = append(rs, c.jsonRpc(req))
+			}
+			resp = rs
+		} else if err := json.Unmarshal(body, &req); err == nil {
+			resp = c.jsonRpc(req)
+		} else {
+			resp = rpc_model.DaemonRPCResponse{
+				Jsonrpc: "2.0",
+				Error:   &rpc_model.DaemonRPCErr{Code: -32700, Message: "Parse error"},
+			}
+		}
+	case "/get_transaction_pool":
+		resp = rpc_model.DaemonResponseBodyGetTransactionPool{Transactions: c.pool, Status: "OK"}
+	case "/get_transaction_pool_stats":
+		resp = rpc_model.DaemonResponseBodyGetTransactionPoolStats{PoolStats: c.poolStats(), Status: "OK"}
+	case "/get_peer_list":
+		resp = c.peerList()
+	case "/get_net_stats":
+		resp = c.netStats()
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,156 @@
+package demo
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/moneronodo/sshui/internal/backend/daemonrpc"
+	"github.com/moneronodo/sshui/internal/backend/i_moneropay"
+	i_lws "github.com/moneronodo/sshui/internal/backend/lws"
+	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/screens"
+)
+
+// In-process stand-ins for monerod, the Nodo D-Bus service, systemd,
+// monero-lws-admin and MoneroPay, so sshui can run on a machine that has
+// none of them.
+
+const demoConfig = `{
+	"config": {
+		"timezone": "UTC",
+		"in_peers": 32,
+		"out_peers": 12,
+		"limit_rate_up": 2048,
+		"limit_rate_down": 8192,
+		"rpc_enabled": "FALSE",
+		"rpcu": "nodo",
+		"rpcp": "",
+		"anon_rpc": "FALSE",
+		"tor_enabled": "TRUE",
+		"tor_global_enabled": "FALSE",
+		"i2p_enabled": "FALSE",
+		"tor_address": "nodoxmrdemo7ca4e2kvmbt4l3h5pd6c5gkx7xvyvjg5ss5rgfd5dyqd.onion",
+		"i2p_address": "nododemo4fxd2lzq6c2krmtjb2hvwzfh6ycrtp3k5tf2dmjz3wpa.b32.i2p",
+		"banlists": {
+			"boog900": "TRUE",
+			"dns": "TRUE",
+			"gui-xmr-pm": "FALSE"
+		},
+		"moneropay": {
+			"enabled": "TRUE",
+			"deposit_address": ""
+		}
+	}
+}
+`
+
+// Serves requests with h without going through the network
+type handlerTransport struct {
+	h http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	rec := httptest.NewRecorder()
+	t.h.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// Deterministic pseudo-random data so the same block or account looks the
+// same every time it is asked for.
+func digest(parts ...any) []byte {
+	d := sha256.Sum256(fmt.Append(nil, parts...))
+	return d[:]
+}
+
+func hash(parts ...any) string {
+	return hex.EncodeToString(digest(parts...))
+}
+
+func number(parts ...any) uint64 {
+	return binary.BigEndian.Uint64(digest(parts...))
+}
+
+const base58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// A 95 character address starting with prefix, '4' for standard and '8'
+// for subaddresses.
+func address(prefix byte, parts ...any) string {
+	b := []byte{prefix}
+	for i := 0; len(b) < 95; i++ {
+		for _, c := range digest(append(parts, i)...) {
+			if len(b) == 95 {
+				break
+			}
+			b = append(b, base58[int(c)%len(base58)])
+		}
+	}
+	// second character of mainnet addresses
+	b[1] = "123456789AB"[b[1]%11]
+	return string(b)
+}
+
+// The simulators, in place of the backends sshui talks to
+type Backends struct {
+	Daemon *daemonrpc.Client
+	Bus    screens.SystemBus
+	// passes the signals of the bus to the program
+	Listen    func(prog *tea.Program)
+	LWS       i_lws.Admin
+	Moneropay i_moneropay.Store
+	Systemd   screens.ServiceManager
+}
+
+// Starts a simulator for every backend and points the config at a
+// throwaway copy. Returns them and a function removing that copy.
+func Start() (*Backends, func(), error) {
+	dir, err := os.MkdirTemp("", "sshui-demo")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	base.Opts.ConfigPath = filepath.Join(dir, "config.json")
+	if err := os.WriteFile(base.Opts.ConfigPath, []byte(demoConfig), 0o644); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	// the marker is only there once the device has been set up
+	if err := os.WriteFile(filepath.Join(dir, "firstboot"), nil, 0o644); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	// shells open in the throwaway directory, as whoever runs the demo
+	base.Opts.MoneroDir = dir
+	if u, err := user.Current(); err == nil {
+		base.Opts.ShellUser = u.Username
+	}
+
+	c := newChain()
+	mp := newMoneropay(c)
+	bus := newBus()
+	return &Backends{
+		Daemon: daemonrpc.NewClientTransport(base.Opts.DaemonUrl, handlerTransport{c}),
+		Bus:    bus,
+		Listen: bus.Listen,
+		LWS:    i_lws.Admin{Run: newLws(c).run},
+		Moneropay: i_moneropay.Store{
+			Url:    base.Opts.MoneropayUrl,
+			Client: &http.Client{Timeout: 5 * time.Second, Transport: handlerTransport{mp}},
+			List:   mp.txList,
+		},
+		Systemd: newUnits(),
+	}, cleanup, nil
+}
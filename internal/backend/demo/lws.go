@@ -0,0 +1,204 @@
+package demo
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/model/lws"
+)
+
+// a new account request every so often
+const lwsRequestRate = 2 * time.Minute
+
+type lwsAccount struct {
+	address    string
+	status     string
+	scanHeight uint64
+	accessTime time.Time
+}
+
+// monero-lws-admin over a database whose scanner keeps up with the chain
+type lwsSim struct {
+	mu        sync.Mutex
+	chain     *chain
+	accounts  []*lwsAccount
+	requests  []lws.LwsRequest
+	lastReqAt time.Time
+}
+
+var errExit = errors.New("exit status 1")
+
+func newLws(c *chain) *lwsSim {
+	l := &lwsSim{chain: c, lastReqAt: c.start}
+	for i, status := range []string{"active", "active", "active", "inactive"} {
+		l.accounts = append(l.accounts, &lwsAccount{
+			address:    address('4', "lws", i),
+			status:     status,
+			scanHeight: chainTop - 200_000 + number("scan", i)%150_000,
+			accessTime: c.start.Add(-time.Duration(number("access", i)%72) * time.Hour),
+		})
+	}
+	l.requests = append(l.requests, lws.LwsRequest{
+		Address:     address('4', "lws request", 0),
+		StartHeight: int32(chainTop - 20_000),
+	})
+	return l
+}
+
+func (l *lwsSim) update() {
+	top := l.chain.height()
+	for _, a := range l.accounts {
+		if a.status == "active" {
+			// scanning catches up a thousand blocks at a time
+			a.scanHeight = min(top, a.scanHeight+1_000)
+		}
+	}
+	for t := l.lastReqAt.Add(lwsRequestRate); t.Before(time.Now()); t = t.Add(lwsRequestRate) {
+		l.requests = append(l.requests, lws.LwsRequest{
+			Address:     address('4', "lws request", t.Unix()),
+			StartHeight: int32(l.chain.heightAt(t)),
+		})
+		l.lastReqAt = t
+	}
+}
+
+func (l *lwsSim) find(address string) *lwsAccount {
+	i := slices.IndexFunc(l.accounts, func(a *lwsAccount) bool {
+		return a.address == address
+	})
+	if i < 0 {
+		return nil
+	}
+	return l.accounts[i]
+}
+
+func (l *lwsSim) listAccounts() any {
+	list := map[string][]lws.LwsAccount{
+		"active":   {},
+		"inactive": {},
+		"hidden":   {},
+	}
+	for _, a := range l.accounts {
+		list[a.status] = append(list[a.status], lws.LwsAccount{
+			Address:    a.address,
+			ScanHeight: int32(a.scanHeight),
+			AccessTime: a.accessTime.Unix(),
+		})
+	}
+	return list
+}
+
+func (l *lwsSim) addAccount(address, viewkey string) (any, error) {
+	if !base.ValidateAddr(address) {
+		return "Invalid base58 public address - wrong length", errExit
+	}
+	if key, err := hex.DecodeString(viewkey); err != nil || len(key) != 32 {
+		return "View key has invalid hex", errExit
+	}
+	if l.find(address) != nil {
+		return "Account already exists", errExit
+	}
+	l.accounts = append(l.accounts, &lwsAccount{
+		address:    address,
+		status:     "active",
+		scanHeight: l.chain.height(),
+		accessTime: time.Now(),
+	})
+	return lws.LwsUpdated{Updated: []lws.LwsAddress{lws.LwsAddress(address)}}, nil
+}
+
+func (l *lwsSim) modify(addresses []string, fn func(a *lwsAccount)) any {
+	updated := lws.LwsUpdated{Updated: []lws.LwsAddress{}}
+	for _, addr := range addresses {
+		if a := l.find(addr); a != nil {
+			fn(a)
+			updated.Updated = append(updated.Updated, lws.LwsAddress(addr))
+		}
+	}
+	return updated
+}
+
+// Takes or drops the pending requests for addresses
+func (l *lwsSim) answer(addresses []string, accept bool) any {
+	updated := lws.LwsUpdated{Updated: []lws.LwsAddress{}}
+	l.requests = slices.DeleteFunc(l.requests, func(r lws.LwsRequest) bool {
+		if !slices.Contains(addresses, r.Address) {
+			return false
+		}
+		if accept {
+			l.accounts = append(l.accounts, &lwsAccount{
+				address:    r.Address,
+				status:     "active",
+				scanHeight: uint64(r.StartHeight),
+				accessTime: time.Now(),
+			})
+		}
+		updated.Updated = append(updated.Updated, lws.LwsAddress(r.Address))
+		return true
+	})
+	return updated
+}
+
+func (l *lwsSim) command(args []string) (any, error) {
+	if len(args) == 0 {
+		return "no command given", errExit
+	}
+	switch cmd, args := args[0], args[1:]; {
+	case cmd == "list_accounts":
+		return l.listAccounts(), nil
+	case cmd == "list_requests":
+		return map[string][]lws.LwsRequest{
+			"create": l.requests,
+			"import": {},
+		}, nil
+	case cmd == "add_account" && len(args) == 2:
+		return l.addAccount(args[0], args[1])
+	case cmd == "modify_account_status" && len(args) > 1:
+		status := args[0]
+		if status != "active" && status != "inactive" && status != "hidden" {
+			return "Invalid account status " + status, errExit
+		}
+		return l.modify(args[1:], func(a *lwsAccount) {
+			a.status = status
+		}), nil
+	case cmd == "rescan" && len(args) > 1:
+		height, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return "Invalid height " + args[0], errExit
+		}
+		return l.modify(args[1:], func(a *lwsAccount) {
+			a.scanHeight = height
+		}), nil
+	case (cmd == "accept_requests" || cmd == "reject_requests") &&
+		len(args) > 1 && args[0] == "create":
+		return l.answer(args[1:], cmd == "accept_requests"), nil
+	}
+	return fmt.Sprintf("Invalid command %q", strings.Join(args, " ")), errExit
+}
+
+// Answers like monero-lws-admin: JSON on success, a message on failure
+func (l *lwsSim) run(arguments ...string) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.update()
+	args := slices.DeleteFunc(slices.Clone(arguments), func(a string) bool {
+		return strings.HasPrefix(a, "--")
+	})
+	out, err := l.command(args)
+	if msg, ok := out.(string); ok {
+		return []byte(msg), err
+	}
+	data, jerr := json.MarshalIndent(out, "", "  ")
+	if jerr != nil {
+		return nil, jerr
+	}
+	return data, err
+}
@@ -0,0 +1,198 @@
+package demo
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moneronodo/sshui/internal/backend/i_moneropay"
+	"github.com/moneronodo/sshui/internal/model/moneropay"
+)
+
+const (
+	// a shop checkout every so often
+	receiverRate = 45 * time.Second
+	// confirmations until received funds unlock
+	unlockConfirmations = 10
+)
+
+var receiverItems = []struct {
+	description string
+	expected    uint64
+}{
+	{"Coffee, large", 12_500_000_000},
+	{"Sticker pack", 8_000_000_000},
+	{"Nodo T-shirt", 95_000_000_000},
+	{"Hosting, 1 month", 310_000_000_000},
+	{"Donation", 0},
+	{"Sourdough loaf", 21_000_000_000},
+	{"Hardware wallet", 1_150_000_000_000},
+	{"Invoice #1042", 2_400_000_000_000},
+}
+
+type payment struct {
+	amount uint64
+	height uint64
+	at     time.Time
+	hash   string
+}
+
+type receiver struct {
+	index       int
+	subaddress  string
+	expected    uint64
+	description string
+	createdAt   time.Time
+	payments    []payment
+}
+
+// MoneroPay with a wallet that keeps getting paid
+type moneropaySim struct {
+	mu        sync.Mutex
+	chain     *chain
+	receivers []*receiver
+	lastAt    time.Time
+}
+
+func newMoneropay(c *chain) *moneropaySim {
+	m := &moneropaySim{chain: c, lastAt: c.start}
+	for i := range 4 {
+		m.addReceiver(c.start.Add(-time.Duration(4-i) * 37 * time.Minute))
+	}
+	return m
+}
+
+func (m *moneropaySim) addReceiver(at time.Time) {
+	i := len(m.receivers)
+	item := receiverItems[number("item", i)%uint64(len(receiverItems))]
+	m.receivers = append(m.receivers, &receiver{
+		index:       i + 1,
+		subaddress:  address('8', "subaddress", i),
+		expected:    item.expected,
+		description: item.description,
+		createdAt:   at,
+	})
+}
+
+// Customers pay a while after the checkout, some in two parts, a few never.
+func (m *moneropaySim) update() {
+	now := time.Now()
+	for t := m.lastAt.Add(receiverRate); t.Before(now); t = t.Add(receiverRate) {
+		m.addReceiver(t)
+		m.lastAt = t
+	}
+	for _, r := range m.receivers {
+		n := number("pays", r.index)
+		if n%6 == 0 || len(r.payments) == 2 || (len(r.payments) == 1 && n%3 != 0) {
+			continue
+		}
+		total := r.expected
+		if total == 0 {
+			total = 1_000_000_000 * (1 + n%250)
+		}
+		amount := total
+		if n%3 == 0 {
+			amount /= 2
+		}
+		at := r.createdAt.Add(time.Duration(10+number("delay", r.index)%50) * time.Second)
+		if len(r.payments) == 1 {
+			at = r.payments[0].at.Add(time.Minute)
+			amount = total - r.payments[0].amount
+		}
+		if at.After(now) {
+			continue
+		}
+		r.payments = append(r.payments, payment{
+			amount: amount,
+			height: m.chain.heightAt(at),
+			at:     at,
+			hash:   hash("payment", r.index, len(r.payments)),
+		})
+	}
+}
+
+func (m *moneropaySim) receive(r *receiver) moneropay.MoneropayReceive {
+	j := moneropay.MoneropayReceive{
+		Description: r.description,
+		CreatedAt:   r.createdAt,
+	}
+	j.Amount.Expected = r.expected
+	top := m.chain.height()
+	for _, p := range r.payments {
+		var conf uint64
+		if top > p.height {
+			conf = top - p.height
+		}
+		locked := conf < unlockConfirmations
+		j.Transactions = append(j.Transactions, moneropay.MoneropayReceiveTx{
+			Amount:        p.amount,
+			Confirmations: uint32(conf),
+			Fee:           30_000_000 + number("fee", p.hash)%40_000_000,
+			Timestamp:     p.at,
+			TxHash:        p.hash,
+			Locked:        locked,
+		})
+		j.Amount.Covered.Total += p.amount
+		if !locked {
+			j.Amount.Covered.Unlocked += p.amount
+		}
+	}
+	j.Complete = len(r.payments) > 0 && j.Amount.Covered.Unlocked >= r.expected
+	return j
+}
+
+// The newest receivers, as read from the MoneroPay database
+func (m *moneropaySim) txList() []i_moneropay.Transaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.update()
+	var txs []i_moneropay.Transaction
+	for _, r := range slices.Backward(m.receivers) {
+		if len(txs) == i_moneropay.TxListSize {
+			break
+		}
+		txs = append(txs, i_moneropay.Transaction{
+			Subaddress:  r.subaddress,
+			Expected:    r.expected,
+			Description: r.description,
+			CreatedAt:   r.createdAt,
+		})
+	}
+	return txs
+}
+
+func (m *moneropaySim) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.update()
+	var resp any
+	switch {
+	case r.URL.Path == "/health":
+		resp = map[string]any{
+			"status": http.StatusOK,
+			"services": map[string]bool{
+				"walletrpc":  true,
+				"postgresql": false,
+				"sqlite":     true,
+			},
+		}
+	case strings.HasPrefix(r.URL.Path, "/receive/"):
+		addr := strings.TrimPrefix(r.URL.Path, "/receive/")
+		i := slices.IndexFunc(m.receivers, func(r *receiver) bool {
+			return r.subaddress == addr
+		})
+		if i < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			resp = map[string]string{"message": "subaddress not found"}
+			break
+		}
+		resp = m.receive(m.receivers[i])
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,280 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/moneronodo/sshui/internal/backend/i_systemd"
+)
+
+const (
+	journalSize     = 2000
+	chatterEvery    = 3 * time.Second
+	chatterBackfill = 30 * time.Minute
+)
+
+type chatterLine struct {
+	prio   int
+	format string
+}
+
+// What the running units keep logging, the argument is a number below 1000
+var chatter = map[string][]chatterLine{
+	"monerod": {
+		{i_systemd.PrioInfo, "[P2P] Received NOTIFY_NEW_FLUFFY_BLOCK from peer %d"},
+		{i_systemd.PrioInfo, "Found new transaction in pool, %d in total"},
+		{i_systemd.PrioInfo, "Synced up to height 3219%03d"},
+		{i_systemd.PrioNotice, "[P2P] New incoming connection, %d peers"},
+		{i_systemd.PrioWarning, "[P2P] Failed to connect to peer %d: timed out"},
+		{i_systemd.PrioErr, "[RPC] Failed to relay transaction %d: fee too low"},
+		{i_systemd.PrioDebug, "[net.p2p] Sync data returned a new top block candidate (%d blocks ahead)"},
+	},
+	"tor": {
+		{i_systemd.PrioNotice, "Heartbeat: Tor's uptime is 3 days 4:%02d hours, with 12 circuits open."},
+		{i_systemd.PrioInfo, "New control connection opened from 127.0.0.1, %d open."},
+		{i_systemd.PrioWarning, "Rejecting request for anonymous connection to private address [scrubbed] on a TransPort or NATDPort. (%d)"},
+		{i_systemd.PrioDebug, "circuit_build_times: %d ms"},
+	},
+	"monero-lws": {
+		{i_systemd.PrioInfo, "Updated %d account(s)"},
+		{i_systemd.PrioInfo, "Scanned up to block 3219%03d"},
+		{i_systemd.PrioWarning, "Connection to daemon lost, retrying in %d ms"},
+		{i_systemd.PrioDebug, "Processing %d outputs"},
+	},
+	"sshd": {
+		{i_systemd.PrioInfo, "Accepted publickey for nodo from 192.168.1.%d port 51544 ssh2"},
+		{i_systemd.PrioInfo, "pam_unix(sshd:session): session opened for user nodo(uid=%d)"},
+		{i_systemd.PrioNotice, "Invalid user admin from 45.33.12.%d port 40522"},
+	},
+	"moneropay": {
+		{i_systemd.PrioInfo, "GET /health 200 %dus"},
+		{i_systemd.PrioInfo, "Checked %d subaddresses for incoming transfers"},
+		{i_systemd.PrioWarning, "monero-wallet-rpc took %d ms to respond"},
+		{i_systemd.PrioErr, "Callback %d to https://shop.example/hook failed: 502 Bad Gateway"},
+	},
+}
+
+type unit struct {
+	active   bool
+	failed   bool
+	enabled  bool
+	since    time.Time
+	restarts uint32
+	journal  []i_systemd.JournalEntry
+	// when the unit last had something to say
+	chatted time.Time
+}
+
+// systemd for the Nodo services. Jobs take a little while and leave a few
+// lines in the journal. i2pd is down on the demo node and fails to start.
+type units struct {
+	mu    sync.Mutex
+	start time.Time
+	units map[string]*unit
+}
+
+func newUnits() *units {
+	u := &units{start: time.Now(), units: map[string]*unit{}}
+	for _, name := range []string{"monerod", "tor", "i2pd", "monero-lws", "sshd", "moneropay"} {
+		u.units[name] = &unit{
+			chatted: u.start.Add(-chatterBackfill),
+			active:  name != "i2pd",
+			enabled: name != "i2pd",
+			since:   u.start.Add(-76*time.Hour - time.Duration(number("since", name)%3600)*time.Second),
+			// systemd only counts restarts it did itself
+			restarts: uint32(number("restarts", name) % 3),
+		}
+	}
+	return u
+}
+
+func (u *units) get(name string) (*unit, error) {
+	un, ok := u.units[name]
+	if !ok {
+		return nil, fmt.Errorf("Unit %s.service not found.", name)
+	}
+	return un, nil
+}
+
+func (u *units) logAt(t time.Time, name string, prio int, format string, args ...any) {
+	un := u.units[name]
+	un.journal = append(un.journal, i_systemd.JournalEntry{
+		Time:     t,
+		Host:     "nodo",
+		Ident:    name,
+		Pid:      fmt.Sprint(1000 + number("pid", name)%30000),
+		Priority: prio,
+		Message:  fmt.Sprintf(format, args...),
+	})
+	un.journal = un.journal[max(0, len(un.journal)-journalSize):]
+}
+
+func (u *units) log(name string, prio int, format string, args ...any) {
+	u.logAt(time.Now(), name, prio, format, args...)
+}
+
+// Makes up what a running unit would have logged since it last did
+func (u *units) chatter(name string) {
+	un := u.units[name]
+	lines := chatter[name]
+	for ; !un.chatted.Add(chatterEvery).After(time.Now()); un.chatted = un.chatted.Add(chatterEvery) {
+		if !un.active || len(lines) == 0 {
+			continue
+		}
+		n := number("chatter", name, un.chatted.Unix())
+		l := lines[n%uint64(len(lines))]
+		u.logAt(un.chatted.Add(chatterEvery), name, l.prio, l.format, n%1000)
+	}
+}
+
+func (u *units) wait(ctx context.Context, name string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Second + time.Duration(number("job", name)%2000)*time.Millisecond):
+		return nil
+	}
+}
+
+func (u *units) stop(ctx context.Context, name string) error {
+	u.mu.Lock()
+	un, err := u.get(name)
+	if err != nil {
+		u.mu.Unlock()
+		return err
+	}
+	if !un.active {
+		u.mu.Unlock()
+		return nil
+	}
+	u.log(name, i_systemd.PrioInfo, "Stopping %s.service...", name)
+	u.mu.Unlock()
+	if err := u.wait(ctx, name); err != nil {
+		return err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	un.active, un.failed = false, false
+	u.log(name, i_systemd.PrioInfo, "Stopped %s.service.", name)
+	return nil
+}
+
+func (u *units) Start(ctx context.Context, name string) error {
+	u.mu.Lock()
+	un, err := u.get(name)
+	if err != nil {
+		u.mu.Unlock()
+		return err
+	}
+	if un.active {
+		u.mu.Unlock()
+		return nil
+	}
+	u.log(name, i_systemd.PrioInfo, "Starting %s.service...", name)
+	u.mu.Unlock()
+	if err := u.wait(ctx, name); err != nil {
+		return err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if name == "i2pd" {
+		u.log(name, i_systemd.PrioErr, "i2pd: Failed to bind to port 4444: Address already in use")
+		u.log(name, i_systemd.PrioNotice, "%s.service: Main process exited, code=exited, status=1/FAILURE", name)
+		u.log(name, i_systemd.PrioWarning, "%s.service: Failed with result 'exit-code'.", name)
+		un.failed = true
+		return &i_systemd.JobError{Unit: name, Result: "failed"}
+	}
+	un.active, un.failed = true, false
+	un.since = time.Now()
+	u.log(name, i_systemd.PrioInfo, "Started %s.service.", name)
+	return nil
+}
+
+func (u *units) Stop(ctx context.Context, name string) error {
+	return u.stop(ctx, name)
+}
+
+func (u *units) Restart(ctx context.Context, name string) error {
+	if err := u.stop(ctx, name); err != nil {
+		return err
+	}
+	return u.Start(ctx, name)
+}
+
+func (u *units) setEnabled(name string, enabled bool) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	un, err := u.get(name)
+	if err != nil {
+		return err
+	}
+	un.enabled = enabled
+	return nil
+}
+
+func (u *units) Enable(ctx context.Context, name string) error {
+	return u.setEnabled(name, true)
+}
+
+func (u *units) Disable(ctx context.Context, name string) error {
+	return u.setEnabled(name, false)
+}
+
+func (u *units) Status(ctx context.Context, name string) (i_systemd.UnitStatus, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	st := i_systemd.UnitStatus{Unit: name}
+	un, err := u.get(name)
+	if err != nil {
+		return st, err
+	}
+	st.ActiveState, st.SubState = "inactive", "dead"
+	if un.active {
+		st.ActiveState, st.SubState = "active", "running"
+		st.ActiveSince = un.since
+		// a few MiB of wobble every status report
+		st.Memory = (20 + number("mem", name)%900) << 20
+		st.Memory += number("mem", name, time.Now().Unix()/5) % (8 << 20)
+	} else if un.failed {
+		st.ActiveState, st.SubState = "failed", "failed"
+	}
+	st.UnitFileState = "disabled"
+	if un.enabled {
+		st.UnitFileState = "enabled"
+	}
+	st.Restarts = un.restarts
+	return st, nil
+}
+
+func (u *units) Entries(name string, lines, priority int) ([]i_systemd.JournalEntry, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	un, err := u.get(name)
+	if err != nil {
+		return nil, err
+	}
+	u.chatter(name)
+	var entries []i_systemd.JournalEntry
+	for i := len(un.journal) - 1; i >= 0 && len(entries) < lines; i-- {
+		if un.journal[i].Priority <= priority {
+			entries = append(entries, un.journal[i])
+		}
+	}
+	slices.Reverse(entries)
+	return entries, nil
+}
+
+func (u *units) Journal(name string, lines int) ([]string, error) {
+	entries, err := u.Entries(name, lines, i_systemd.PrioDebug)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.String()
+	}
+	return out, nil
+}
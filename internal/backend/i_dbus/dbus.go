@@ -1,104 +1,85 @@
 package i_dbus
 
 import (
-	"os"
+	"fmt"
+	"reflect"
 	"strings"
 
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/davecgh/go-spew/spew"
 	dbus "github.com/godbus/dbus/v5"
 	"github.com/moneronodo/sshui/internal/base"
 	dbus_model "github.com/moneronodo/sshui/internal/model/dbus"
 )
 
-func DbusSignal(s *dbus.Signal) dbus_model.DbusSignal {
-	if !strings.HasPrefix(s.Name, "com.moneronodo.embeddedInterface") {
-		return nil
+var logger = base.Logger("dbus")
+
+// The signal s stands for, nil for those of other interfaces and ones sshui
+// doesn't know. Bodies that aren't what the signal carries are an error.
+func DbusSignal(s *dbus.Signal) (dbus_model.DbusSignal, error) {
+	name, ok := strings.CutPrefix(s.Name, nodoInterface+".")
+	if !ok {
+		return nil, nil
 	}
-	switch strings.Split(s.Name, "com.moneronodo.embeddedInterface.")[1] {
+	switch name {
 	case "factoryResetStarted":
-		return dbus_model.FactoryResetStarted{}
+		return dbus_model.FactoryResetStarted{}, nil
 	case "factoryResetCompleted":
-		return dbus_model.FactoryResetCompleted{}
+		return dbus_model.FactoryResetCompleted{}, nil
 	case "factoryResetRequested":
-		return dbus_model.FactoryResetRequested{}
+		return dbus_model.FactoryResetRequested{}, nil
 	case "powerButtonPressDetected":
-		return dbus_model.PowerButtonPressDetected{}
+		return dbus_model.PowerButtonPressDetected{}, nil
 	case "powerButtonReleaseDetected":
-		return dbus_model.PowerButtonReleaseDetected{}
+		return dbus_model.PowerButtonReleaseDetected{}, nil
 	case "moneroLWSListAccountsCompleted":
-		return dbus_model.MoneroLWSListAccountsCompleted{}
+		return dbus_model.MoneroLWSListAccountsCompleted{}, nil
 	case "moneroLWSListRequestsCompleted":
-		return dbus_model.MoneroLWSListRequestsCompleted{}
+		return dbus_model.MoneroLWSListRequestsCompleted{}, nil
 	case "moneroLWSAccountAdded":
-		return dbus_model.MoneroLWSAccountAdded{}
+		return dbus_model.MoneroLWSAccountAdded{}, nil
 	case "connectionStatusChanged":
-		return dbus_model.ConnectionStatusChanged{}
+		return dbus_model.ConnectionStatusChanged{}, nil
 	case "startRecoveryNotification":
-		return dbus_model.StartRecoveryNotification{
-			Message: s.Body[0].(string),
-		}
+		msg, err := bodyString(s)
+		return dbus_model.StartRecoveryNotification{Message: msg}, err
 	case "serviceManagerNotification":
-		return dbus_model.ServiceManagerNotification{
-			Message: s.Body[0].(string),
-		}
+		msg, err := bodyString(s)
+		return dbus_model.ServiceManagerNotification{Message: msg}, err
 	case "hardwareStatusReadyNotification":
-		return dbus_model.HardwareStatusReadyNotification{
-			Message: s.Body[0].(string),
-		}
+		msg, err := bodyString(s)
+		return dbus_model.HardwareStatusReadyNotification{Message: msg}, err
 	case "serviceStatusReadyNotification":
-		return dbus_model.ServiceStatusReadyNotification{
-			Message: s.Body[0].(string),
-		}
+		msg, err := bodyString(s)
+		return dbus_model.ServiceStatusReadyNotification{Message: msg}, err
 	case "passwordChangeStatus":
-		return dbus_model.PasswordChangeStatus{
-			Status: s.Body[0].(int),
-		}
+		status, err := bodyInt(s)
+		return dbus_model.PasswordChangeStatus{Status: status}, err
 	default:
-		return nil
+		return nil, nil
 	}
 }
 
-func Signals(prog *tea.Program) {
-	conn, err := dbus.SystemBus()
-	if err != nil {
-		spew.Fprintln(base.Dump, "Dbus: ", err)
-		os.Exit(1)
+func bodyString(s *dbus.Signal) (string, error) {
+	if len(s.Body) == 0 {
+		return "", fmt.Errorf("%s: empty body", s.Name)
 	}
-	defer conn.Close()
-
-	if err = conn.AddMatchSignal(
-		dbus.WithMatchObjectPath("/com/monero/nodo"),
-		dbus.WithMatchInterface("com.moneronodo.embeddedInterface"),
-	); err != nil {
-		spew.Fprintln(base.Dump, "Dbus: ", err)
-		os.Exit(1)
-	}
-
-	c := make(chan *dbus.Signal, 10)
-	conn.Signal(c)
-	for v := range c {
-		sig := DbusSignal(v)
-		msg := dbus_model.DbusSignalMsg{
-			Signal: sig,
-		}
-		prog.Send(msg)
+	v, ok := s.Body[0].(string)
+	if !ok {
+		return "", fmt.Errorf("%s: %T in body, want string", s.Name, s.Body[0])
 	}
+	return v, nil
 }
 
-func Call(notification string, args ...any) {
-	spew.Fprintf(base.Dump, "Call %s\n", notification)
-	conn, err := dbus.SystemBus()
-	if err != nil {
-		spew.Fdump(base.Dump, err)
+// Any integer, the Nodo service and the demo don't agree on the width
+func bodyInt(s *dbus.Signal) (int, error) {
+	if len(s.Body) == 0 {
+		return 0, fmt.Errorf("%s: empty body", s.Name)
 	}
-	defer conn.Close()
-
-	obj := conn.Object("com.monero.nodo", "/com/monero/nodo")
-	call := obj.Call("com.moneronodo.embeddedInterface."+notification, 0, args...)
-	if call.Err != nil {
-		spew.Fdump(base.Dump, call.Err)
-	} else {
-		spew.Fdump(base.Dump, call)
+	v := reflect.ValueOf(s.Body[0])
+	switch {
+	case v.CanInt():
+		return int(v.Int()), nil
+	case v.CanUint():
+		return int(v.Uint()), nil
 	}
+	return 0, fmt.Errorf("%s: %T in body, want integer", s.Name, s.Body[0])
 }
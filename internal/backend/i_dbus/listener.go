@@ -0,0 +1,112 @@
+package i_dbus
+
+import (
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	dbus "github.com/godbus/dbus/v5"
+	dbus_model "github.com/moneronodo/sshui/internal/model/dbus"
+)
+
+const (
+	listenMinBackoff = time.Second
+	listenMaxBackoff = time.Minute
+)
+
+var errBusClosed = errors.New("system bus connection closed")
+
+func deliver(prog *tea.Program, v *dbus.Signal) {
+	sig, err := DbusSignal(v)
+	if err != nil {
+		logger.Warn("dropping signal", "err", err)
+		return
+	}
+	if sig != nil {
+		prog.Send(dbus_model.DbusSignalMsg{Signal: sig})
+	}
+}
+
+// Passes the signals of the embedded interface to prog for as long as it
+// runs. Whenever the system bus or the Nodo service goes away prog hears
+// of it and the listener connects again, waiting longer each time.
+func Signals(prog *tea.Program) {
+	backoff := listenMinBackoff
+	for {
+		start := time.Now()
+		err := listen(prog)
+		prog.Send(dbus_model.BusStatusMsg{Online: false, Err: err})
+		// a connection that lasted isn't part of a run of failures
+		if time.Since(start) > listenMaxBackoff {
+			backoff = listenMinBackoff
+		}
+		logger.Warn("signal listener stopped", "err", err, "retry", backoff)
+		time.Sleep(backoff)
+		backoff = min(2*backoff, listenMaxBackoff)
+	}
+}
+
+// Passes the signals from c to prog the way Signals does, for stand-ins of
+// the bus that are always there
+func Forward(prog *tea.Program, c <-chan *dbus.Signal) {
+	prog.Send(dbus_model.BusStatusMsg{Online: true})
+	for v := range c {
+		deliver(prog, v)
+	}
+}
+
+// Listens on a connection of its own until it breaks. Besides the Nodo
+// signals it follows who owns the Nodo name, that's how a restart of the
+// embedded daemon shows.
+func listen(prog *tea.Program) error {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(nodoPath),
+		dbus.WithMatchInterface(nodoInterface),
+	); err != nil {
+		return err
+	}
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchSender("org.freedesktop.DBus"),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg(0, nodoDest),
+	); err != nil {
+		return err
+	}
+	c := make(chan *dbus.Signal, 10)
+	conn.Signal(c)
+
+	var owned bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, nodoDest).Store(&owned); err != nil {
+		return err
+	}
+	report := func(online bool) {
+		if online {
+			logger.Info("embedded interface online")
+			prog.Send(dbus_model.BusStatusMsg{Online: true})
+		} else {
+			logger.Warn("embedded interface offline")
+			prog.Send(dbus_model.BusStatusMsg{Online: false, Err: errors.New(nodoDest + " is not running")})
+		}
+	}
+	report(owned)
+
+	// the channel is closed along with the connection
+	for v := range c {
+		if v.Name != "org.freedesktop.DBus.NameOwnerChanged" {
+			deliver(prog, v)
+			continue
+		}
+		// name, old owner, new owner
+		var name, old, owner string
+		if err := dbus.Store(v.Body, &name, &old, &owner); err == nil && name == nodoDest {
+			report(owner != "")
+		}
+	}
+	return errBusClosed
+}
@@ -0,0 +1,18 @@
+package i_dbus
+
+// A method of the embedded interface with the type of what it returns.
+// Those that report back through a signal return nothing.
+type Method[Resp any] struct {
+	Name string
+}
+
+type NoReply struct{}
+
+var (
+	Restart       = Method[NoReply]{Name: "restart"}
+	Shutdown      = Method[NoReply]{Name: "shutdown"}
+	StartRecovery = Method[NoReply]{Name: "startRecovery"}
+	SetPassword   = Method[NoReply]{Name: "setPassword"}
+	// takes the user's password, which the Nodo service checks
+	FactoryReset = Method[NoReply]{Name: "factoryReset"}
+)
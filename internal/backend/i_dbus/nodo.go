@@ -0,0 +1,123 @@
+package i_dbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	dbus "github.com/godbus/dbus/v5"
+	dbus_model "github.com/moneronodo/sshui/internal/model/dbus"
+)
+
+const (
+	nodoDest      = "com.monero.nodo"
+	nodoPath      = "/com/monero/nodo"
+	nodoInterface = "com.moneronodo.embeddedInterface"
+
+	// for calls whose context has no deadline of its own
+	callTimeout = 10 * time.Second
+)
+
+// Makes calls to the Nodo embedded interface. *Nodo does it over the
+// system bus, fakes can answer them directly.
+type Caller interface {
+	Call(ctx context.Context, method string, args ...any) ([]any, error)
+}
+
+// The Nodo embedded interface on the system bus. The connection is opened
+// on the first call and shared by the ones after, a broken one is replaced.
+type Nodo struct {
+	mu   sync.Mutex
+	conn *dbus.Conn
+}
+
+func NewNodo() *Nodo {
+	return &Nodo{}
+}
+
+func (n *Nodo) connection() (*dbus.Conn, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn != nil && n.conn.Connected() {
+		return n.conn, nil
+	}
+	// a private connection, so Close doesn't pull the shared one from
+	// under the signal listener
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the system bus: %w", err)
+	}
+	n.conn = conn
+	return conn, nil
+}
+
+func (n *Nodo) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn == nil {
+		return nil
+	}
+	err := n.conn.Close()
+	n.conn = nil
+	return err
+}
+
+// Calls method with args and returns what it returned
+func (n *Nodo) Call(ctx context.Context, method string, args ...any) ([]any, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, callTimeout)
+		defer cancel()
+	}
+	logger.Info("call", "method", method)
+	body, err := n.call(ctx, method, args...)
+	if err != nil {
+		logger.Error("call failed", "method", method, "err", err)
+		return nil, err
+	}
+	logger.Debug("call returned", "method", method, "body", body)
+	return body, nil
+}
+
+func (n *Nodo) call(ctx context.Context, method string, args ...any) ([]any, error) {
+	conn, err := n.connection()
+	if err != nil {
+		return nil, err
+	}
+	call := conn.Object(nodoDest, nodoPath).CallWithContext(ctx, nodoInterface+"."+method, 0, args...)
+	if errors.Is(call.Err, context.DeadlineExceeded) {
+		return nil, errors.New("no answer from the Nodo service")
+	}
+	return call.Body, call.Err
+}
+
+// Calls m with args and stores what it returned in a Resp
+func Call[Resp any](ctx context.Context, c Caller, m Method[Resp], args ...any) (Resp, error) {
+	var resp Resp
+	body, err := c.Call(ctx, m.Name, args...)
+	if err != nil {
+		return resp, err
+	}
+	if _, none := any(resp).(NoReply); none {
+		return resp, nil
+	}
+	if err := dbus.Store(body, &resp); err != nil {
+		return resp, fmt.Errorf("%s returned %v: %w", m.Name, body, err)
+	}
+	return resp, nil
+}
+
+// Like Call, but in the background, the outcome arrives as a DbusCallMsg.
+func CallCmd[Resp any](c Caller, m Method[Resp], args ...any) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := Call(context.Background(), c, m, args...)
+		return dbus_model.DbusCallMsg{
+			Method: m.Name,
+			Result: resp,
+			Err:    err,
+		}
+	}
+}
@@ -7,17 +7,19 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/davecgh/go-spew/spew"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/moneronodo/sshui/internal/base"
 	"github.com/moneronodo/sshui/internal/model/moneropay"
 )
 
+var logger = base.Logger("mpay")
+
 const (
-	connMoneropay = "file:///home/nodo/moneropay.sqlite?immutable=1"
-	TxListSize    = 10
+	TxListSize = 10
 )
 
+var client = &http.Client{Timeout: 5 * time.Second}
+
 type MpayTxUpdateMsg struct {
 	Transaction Transaction
 	Index       int
@@ -38,11 +40,32 @@ type Transaction struct {
 	Complete    bool
 }
 
-func GetTxList() []Transaction {
+// A MoneroPay instance: its API at Url and its sqlite database at DB
+type Store struct {
+	Url string
+	DB  string
+	// For the API instead of the default client when set
+	Client *http.Client
+	// Lists the receivers instead of the database when set
+	List func() []Transaction
+}
+
+func (s Store) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return client
+}
+
+// The newest receivers, at most TxListSize
+func (s Store) TxList() []Transaction {
+	if s.List != nil {
+		return s.List()
+	}
 	txs := []Transaction{}
-	db, err := sql.Open("sqlite3", connMoneropay)
+	db, err := sql.Open("sqlite3", "file://"+s.DB+"?immutable=1")
 	if err != nil {
-		spew.Fdump(base.Dump, err)
+		logger.Error("opening database failed", "db", s.DB, "err", err)
 		return txs
 	}
 	defer db.Close()
@@ -58,7 +81,7 @@ func GetTxList() []Transaction {
 		TxListSize,
 	)
 	if err != nil {
-		spew.Fdump(base.Dump, err)
+		logger.Error("listing receivers failed", "err", err)
 		return txs
 	}
 	defer rows.Close()
@@ -66,7 +89,7 @@ func GetTxList() []Transaction {
 		tx := Transaction{}
 		err := rows.Scan(&tx.Subaddress, &tx.Expected, &tx.Description, &tx.CreatedAt)
 		if err != nil {
-			spew.Fdump(base.Dump, err)
+			logger.Warn("reading receiver failed", "err", err)
 		} else {
 			txs = append(txs, tx)
 		}
@@ -74,18 +97,32 @@ func GetTxList() []Transaction {
 	return txs
 }
 
-func GetHealth(url string) *moneropay.MoneropayHealth {
-	var j = &moneropay.MoneropayHealth{}
-	c := &http.Client{Timeout: 3 * time.Second}
-	resp, err := c.Get(url)
+func (s Store) Health() moneropay.MoneropayHealth {
+	var j = moneropay.MoneropayHealth{}
+	resp, err := s.client().Get(s.Url + "/health")
 	if err != nil {
-		spew.Fprintf(base.Dump, "http: %v\n", err)
+		logger.Warn("health check failed", "err", err)
 		return j
 	}
 	defer resp.Body.Close()
 	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(j); err != nil {
-		spew.Fprintf(base.Dump, "Decode: %v\n", err)
+	if err := dec.Decode(&j); err != nil {
+		logger.Warn("decoding health failed", "err", err)
 	}
 	return j
 }
+
+func (s Store) Receive(address string) (moneropay.MoneropayReceive, error) {
+	j := moneropay.MoneropayReceive{}
+	resp, err := s.client().Get(s.Url + "/receive/" + address)
+	if err != nil {
+		return j, err
+	}
+	defer resp.Body.Close()
+	dec := json.NewDecoder(resp.Body)
+	if err := dec.Decode(&j); err != nil {
+		logger.Warn("decoding receive failed", "address", address, "err", err)
+		return j, err
+	}
+	return j, nil
+}
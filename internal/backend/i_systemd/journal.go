@@ -0,0 +1,126 @@
+package i_systemd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// Syslog priorities, most severe first
+const (
+	PrioEmerg = iota
+	PrioAlert
+	PrioCrit
+	PrioErr
+	PrioWarning
+	PrioNotice
+	PrioInfo
+	PrioDebug
+)
+
+var PriorityNames = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+type JournalEntry struct {
+	Time     time.Time
+	Host     string
+	Ident    string
+	Pid      string
+	Priority int
+	Message  string
+}
+
+// Like journalctl -o short
+func (e JournalEntry) String() string {
+	ident := e.Ident
+	if e.Pid != "" {
+		ident += "[" + e.Pid + "]"
+	}
+	return fmt.Sprintf("%s %s %s: %s", e.Time.Format("Jan 02 15:04:05"), e.Host, ident, e.Message)
+}
+
+// journalctl -o json gives every field as a string, except for messages
+// that aren't valid UTF-8, which come as an array of bytes
+type journalField string
+
+func (f *journalField) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = journalField(s)
+		return nil
+	}
+	var ints []int
+	if err := json.Unmarshal(data, &ints); err != nil {
+		// null, or several values for the field
+		return nil
+	}
+	b := make([]byte, 0, len(ints))
+	for _, i := range ints {
+		b = append(b, byte(i))
+	}
+	*f = journalField(b)
+	return nil
+}
+
+type journalRecord struct {
+	Realtime journalField `json:"__REALTIME_TIMESTAMP"`
+	Host     journalField `json:"_HOSTNAME"`
+	Ident    journalField `json:"SYSLOG_IDENTIFIER"`
+	Pid      journalField `json:"_PID"`
+	Priority journalField `json:"PRIORITY"`
+	Message  journalField `json:"MESSAGE"`
+}
+
+func parseJournal(out []byte) ([]JournalEntry, error) {
+	var entries []JournalEntry
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var r journalRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return entries, err
+		}
+		e := JournalEntry{
+			Host:     string(r.Host),
+			Ident:    string(r.Ident),
+			Pid:      string(r.Pid),
+			Priority: PrioInfo,
+			Message:  string(r.Message),
+		}
+		if us, err := strconv.ParseInt(string(r.Realtime), 10, 64); err == nil {
+			e.Time = time.UnixMicro(us)
+		}
+		if p, err := strconv.Atoi(string(r.Priority)); err == nil {
+			e.Priority = p
+		}
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
+
+// The last lines unit logged at priority or more severe, oldest first
+func (*Manager) Entries(unit string, lines, priority int) ([]JournalEntry, error) {
+	out, err := exec.Command("/usr/bin/journalctl", "-u", unitName(unit),
+		"-n", strconv.Itoa(lines), "-p", strconv.Itoa(priority),
+		"--no-pager", "-o", "json").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseJournal(out)
+}
+
+// The last lines unit logged, formatted
+func (m *Manager) Journal(unit string, lines int) ([]string, error) {
+	entries, err := m.Entries(unit, lines, PrioDebug)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.String()
+	}
+	return out, nil
+}
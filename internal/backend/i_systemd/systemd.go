@@ -0,0 +1,227 @@
+package i_systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	dbus "github.com/godbus/dbus/v5"
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+var logger = base.Logger("systemd")
+
+const (
+	sdDest    = "org.freedesktop.systemd1"
+	sdPath    = "/org/freedesktop/systemd1"
+	sdManager = "org.freedesktop.systemd1.Manager"
+	sdUnit    = "org.freedesktop.systemd1.Unit"
+	sdService = "org.freedesktop.systemd1.Service"
+)
+
+// A job that finished with something other than "done", e.g. "failed",
+// "timeout" or "dependency".
+type JobError struct {
+	Unit   string
+	Result string
+}
+
+func (e *JobError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Unit, e.Result)
+}
+
+type UnitStatus struct {
+	Unit        string
+	ActiveState string
+	SubState    string
+	// enabled, disabled, static...
+	UnitFileState string
+	ActiveSince   time.Time
+	// zero when systemd doesn't account memory for the unit
+	Memory   uint64
+	Restarts uint32
+}
+
+func unitName(unit string) string {
+	if strings.Contains(unit, ".") {
+		return unit
+	}
+	return unit + ".service"
+}
+
+// Talks to org.freedesktop.systemd1 on the system bus. Status and unit
+// file calls share a connection opened on the first of them, a broken one
+// is replaced. Jobs use connections of their own.
+type Manager struct {
+	mu   sync.Mutex
+	conn *dbus.Conn
+}
+
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+func (m *Manager) connection() (*dbus.Conn, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conn != nil && m.conn.Connected() {
+		return m.conn, nil
+	}
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the system bus: %w", err)
+	}
+	m.conn = conn
+	return conn, nil
+}
+
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conn == nil {
+		return nil
+	}
+	err := m.conn.Close()
+	m.conn = nil
+	return err
+}
+
+// Queues a start, stop or restart job for unit and waits for it to finish
+func job(ctx context.Context, method, unit string) error {
+	// a connection of our own, so the JobRemoved signals are ours to read
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	obj := conn.Object(sdDest, sdPath)
+	if err := obj.CallWithContext(ctx, sdManager+".Subscribe", 0).Err; err != nil {
+		return err
+	}
+	if err := conn.AddMatchSignalContext(ctx,
+		dbus.WithMatchObjectPath(sdPath),
+		dbus.WithMatchInterface(sdManager),
+		dbus.WithMatchMember("JobRemoved"),
+	); err != nil {
+		return err
+	}
+	c := make(chan *dbus.Signal, 16)
+	conn.Signal(c)
+
+	var path dbus.ObjectPath
+	if err := obj.CallWithContext(ctx, sdManager+"."+method, 0,
+		unitName(unit), "replace").Store(&path); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w", unit, ctx.Err())
+		case sig, ok := <-c:
+			if !ok {
+				return errors.New("system bus connection closed")
+			}
+			var (
+				id           uint32
+				jobPath      dbus.ObjectPath
+				name, result string
+			)
+			if sig.Name != sdManager+".JobRemoved" ||
+				dbus.Store(sig.Body, &id, &jobPath, &name, &result) != nil ||
+				jobPath != path {
+				continue
+			}
+			if result != "done" {
+				return &JobError{unit, result}
+			}
+			return nil
+		}
+	}
+}
+
+func (*Manager) Start(ctx context.Context, unit string) error {
+	logger.Info("start", "unit", unit)
+	return job(ctx, "StartUnit", unit)
+}
+
+func (*Manager) Stop(ctx context.Context, unit string) error {
+	logger.Info("stop", "unit", unit)
+	return job(ctx, "StopUnit", unit)
+}
+
+func (*Manager) Restart(ctx context.Context, unit string) error {
+	logger.Info("restart", "unit", unit)
+	return job(ctx, "RestartUnit", unit)
+}
+
+// Calls a unit file method of the manager, then reloads so it takes effect
+func (m *Manager) unitFiles(ctx context.Context, method string, args ...any) error {
+	conn, err := m.connection()
+	if err != nil {
+		return err
+	}
+	obj := conn.Object(sdDest, sdPath)
+	if err := obj.CallWithContext(ctx, sdManager+"."+method, 0, args...).Err; err != nil {
+		return err
+	}
+	return obj.CallWithContext(ctx, sdManager+".Reload", 0).Err
+}
+
+func (m *Manager) Enable(ctx context.Context, unit string) error {
+	logger.Info("enable", "unit", unit)
+	// not runtime only, don't force
+	return m.unitFiles(ctx, "EnableUnitFiles", []string{unitName(unit)}, false, false)
+}
+
+func (m *Manager) Disable(ctx context.Context, unit string) error {
+	logger.Info("disable", "unit", unit)
+	return m.unitFiles(ctx, "DisableUnitFiles", []string{unitName(unit)}, false)
+}
+
+func prop(props map[string]dbus.Variant, name string, dest any) error {
+	v, ok := props[name]
+	if !ok {
+		return fmt.Errorf("no property %s", name)
+	}
+	return v.Store(dest)
+}
+
+func (m *Manager) Status(ctx context.Context, unit string) (UnitStatus, error) {
+	st := UnitStatus{Unit: unit}
+	conn, err := m.connection()
+	if err != nil {
+		return st, err
+	}
+	var path dbus.ObjectPath
+	if err := conn.Object(sdDest, sdPath).CallWithContext(ctx, sdManager+".LoadUnit", 0,
+		unitName(unit)).Store(&path); err != nil {
+		return st, err
+	}
+	obj := conn.Object(sdDest, path)
+	var u, s map[string]dbus.Variant
+	if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0,
+		sdUnit).Store(&u); err != nil {
+		return st, err
+	}
+	if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0,
+		sdService).Store(&s); err != nil {
+		return st, err
+	}
+	prop(u, "ActiveState", &st.ActiveState)
+	prop(u, "SubState", &st.SubState)
+	prop(u, "UnitFileState", &st.UnitFileState)
+	var since, mem uint64
+	if prop(u, "ActiveEnterTimestamp", &since) == nil && since > 0 {
+		st.ActiveSince = time.UnixMicro(int64(since))
+	}
+	// unset is reported as the largest uint64
+	if prop(s, "MemoryCurrent", &mem) == nil && mem != math.MaxUint64 {
+		st.Memory = mem
+	}
+	prop(s, "NRestarts", &st.Restarts)
+	return st, nil
+}
@@ -6,17 +6,26 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/moneronodo/sshui/internal/base"
 	"github.com/moneronodo/sshui/internal/model/lws"
 )
 
-const (
-	prog = "/home/nodo/bin/monero-lws-admin"
-	args = "--db-path=/media/monero/bitmonero/light_wallet_server"
-)
+// monero-lws-admin on the light wallet server database
+type Admin struct {
+	// Runs the admin commands instead of monero-lws-admin when set. It gets
+	// the same arguments and must answer with the same output.
+	Run func(arguments ...string) ([]byte, error)
+}
 
-func command(arguments ...string) ([]byte, error) {
-	a := append([]string{args}, arguments...)
-	c, err := exec.Command(prog, a...).CombinedOutput()
+func (a Admin) command(arguments ...string) ([]byte, error) {
+	args := append([]string{"--db-path=" + base.Opts.LwsDB}, arguments...)
+	run := a.Run
+	if run == nil {
+		run = func(arguments ...string) ([]byte, error) {
+			return exec.Command(base.Opts.LwsAdmin, arguments...).CombinedOutput()
+		}
+	}
+	c, err := run(args...)
 	if err != nil {
 		if strings.HasPrefix(string(c), "View key has invalid hex") {
 			return nil, &lws.LwsViewkeyInvalidErr{}
@@ -32,9 +41,9 @@ func command(arguments ...string) ([]byte, error) {
 	return c, nil
 }
 
-func ListAccounts() (lws.LwsListAccounts, error) {
+func (a Admin) ListAccounts() (lws.LwsListAccounts, error) {
 	accs := lws.LwsListAccounts{}
-	c, err := command("list_accounts")
+	c, err := a.command("list_accounts")
 	if err != nil {
 		return accs, err
 	}
@@ -42,9 +51,9 @@ func ListAccounts() (lws.LwsListAccounts, error) {
 	return accs, err
 }
 
-func ListRequests() (lws.LwsListReqeusts, error) {
+func (a Admin) ListRequests() (lws.LwsListReqeusts, error) {
 	accs := lws.LwsListReqeusts{}
-	c, err := command("list_requests")
+	c, err := a.command("list_requests")
 	if err != nil {
 		return accs, err
 	}
@@ -52,47 +61,47 @@ func ListRequests() (lws.LwsListReqeusts, error) {
 	return accs, err
 }
 
-func AddAccount(address, viewkey string) error {
-	_, err := command("add_account", address, viewkey)
+func (a Admin) AddAccount(address, viewkey string) error {
+	_, err := a.command("add_account", address, viewkey)
 	return err
 }
 
-func DeleteAccount(address string) error {
-	_, err := command("modify_account_status", "hidden", address)
+func (a Admin) DeleteAccount(address string) error {
+	_, err := a.command("modify_account_status", "hidden", address)
 	return err
 }
 
-func DeactivateAccount(address string) error {
-	_, err := command("modify_account_status", "inactive", address)
+func (a Admin) DeactivateAccount(address string) error {
+	_, err := a.command("modify_account_status", "inactive", address)
 	return err
 }
 
-func ReactivateAccount(address string) error {
-	_, err := command("modify_account_status", "active", address)
+func (a Admin) ReactivateAccount(address string) error {
+	_, err := a.command("modify_account_status", "active", address)
 	return err
 }
 
-func Rescan(address string, height int) error {
-	_, err := command("rescan", strconv.Itoa(height), address)
+func (a Admin) Rescan(address string, height int) error {
+	_, err := a.command("rescan", strconv.Itoa(height), address)
 	return err
 }
 
-func AcceptRequest(address ...string) error {
+func (a Admin) AcceptRequest(address ...string) error {
 	if len(address) == 0 {
 		return nil
 	}
 	args := []string{"accept_requests", "create"}
 	args = append(args, address...)
-	_, err := command(args...)
+	_, err := a.command(args...)
 	return err
 }
 
-func RejectRequest(address ...string) error {
+func (a Admin) RejectRequest(address ...string) error {
 	if len(address) == 0 {
 		return nil
 	}
 	args := []string{"reject_requests", "create"}
 	args = append(args, address...)
-	_, err := command(args...)
+	_, err := a.command(args...)
 	return err
 }
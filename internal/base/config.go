@@ -0,0 +1,180 @@
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Flags are stored as the strings "TRUE" and "FALSE" in config.json
+type Flag bool
+
+func (f Flag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Bool(bool(f)))
+}
+
+func (f *Flag) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil || (s != "TRUE" && s != "FALSE") {
+		return &json.UnmarshalTypeError{Value: string(data), Type: reflect.TypeFor[Flag]()}
+	}
+	*f = Flag(GetBool(s))
+	return nil
+}
+
+type MoneropayConfig struct {
+	Enabled        Flag   `json:"enabled"`
+	DepositAddress string `json:"deposit_address"`
+}
+
+type BanlistsConfig struct {
+	Boog900  Flag `json:"boog900"`
+	Dns      Flag `json:"dns"`
+	GuiXmrPm Flag `json:"gui-xmr-pm"`
+}
+
+// The keys of the "config" section sshui knows about. Other keys are left
+// as they are.
+type NodoConfig struct {
+	InPeers          int             `json:"in_peers"`
+	OutPeers         int             `json:"out_peers"`
+	LimitRateUp      int             `json:"limit_rate_up"`
+	LimitRateDown    int             `json:"limit_rate_down"`
+	RpcEnabled       Flag            `json:"rpc_enabled"`
+	Rpcu             string          `json:"rpcu"`
+	Rpcp             string          `json:"rpcp"`
+	AnonRpc          Flag            `json:"anon_rpc"`
+	TorEnabled       Flag            `json:"tor_enabled"`
+	TorGlobalEnabled Flag            `json:"tor_global_enabled"`
+	I2pEnabled       Flag            `json:"i2p_enabled"`
+	TorAddress       string          `json:"tor_address"`
+	I2pAddress       string          `json:"i2p_address"`
+	Timezone         string          `json:"timezone"`
+	Moneropay        MoneropayConfig `json:"moneropay"`
+	Banlists         BanlistsConfig  `json:"banlists"`
+}
+
+type ConfigValueErr struct {
+	Key    string
+	Reason string
+}
+
+func (e *ConfigValueErr) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Reason)
+}
+
+const (
+	maxPeers = 1000
+	// kB/s, monerod takes -1 for its default
+	maxRate = 1_000_000
+)
+
+// Credentials end up in monerod's --rpc-login user:pass and in shell
+// scripts, so keep them to printable characters without separators.
+func checkCredential(key, val string) error {
+	if len(val) > 64 {
+		return &ConfigValueErr{key, "longer than 64 characters"}
+	}
+	if strings.ContainsAny(val, ":'\"`\\$ ") {
+		return &ConfigValueErr{key, "must not contain spaces, quotes, backslashes, $ or :"}
+	}
+	for _, r := range val {
+		if r < 0x21 || r > 0x7e {
+			return &ConfigValueErr{key, "must be printable ASCII"}
+		}
+	}
+	return nil
+}
+
+func (c *NodoConfig) Validate() error {
+	for _, v := range []struct {
+		key      string
+		val      int
+		min, max int
+	}{
+		{"in_peers", c.InPeers, -1, maxPeers},
+		{"out_peers", c.OutPeers, -1, maxPeers},
+		{"limit_rate_up", c.LimitRateUp, -1, maxRate},
+		{"limit_rate_down", c.LimitRateDown, -1, maxRate},
+	} {
+		if v.val < v.min || v.val > v.max {
+			return &ConfigValueErr{v.key, fmt.Sprintf("must be between %d and %d", v.min, v.max)}
+		}
+	}
+	if err := checkCredential("rpcu", c.Rpcu); err != nil {
+		return err
+	}
+	if err := checkCredential("rpcp", c.Rpcp); err != nil {
+		return err
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return &ConfigValueErr{"timezone", "unknown time zone " + c.Timezone}
+		}
+	}
+	if a := c.Moneropay.DepositAddress; a != "" && !ValidateAddr(a) {
+		return &ConfigValueErr{"moneropay.deposit_address", "not a primary address"}
+	}
+	return nil
+}
+
+// Checks the "config" section of a parsed config.json against NodoConfig
+func validateConfig(c map[string]any) error {
+	data, err := json.Marshal(c["config"])
+	if err != nil {
+		return err
+	}
+	var nc NodoConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&nc); err != nil {
+		if te, ok := err.(*json.UnmarshalTypeError); ok {
+			return &ConfigValueErr{te.Field, fmt.Sprintf("invalid value %s", te.Value)}
+		}
+		return err
+	}
+	return nc.Validate()
+}
+
+// Writes through a temporary file in the same directory that is synced and
+// renamed over path, so readers see either the old or the new contents.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	fail := func(err error) error {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return fail(err)
+	}
+	if err := f.Chmod(perm); err != nil {
+		return fail(err)
+	}
+	if err := f.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	// make the rename itself durable
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
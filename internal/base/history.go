@@ -0,0 +1,148 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Every save keeps the config.json it replaces in config.history, named by
+// the time it was replaced. Only the newest Opts.ConfigHistory are kept.
+
+const snapshotLayout = "20060102T150405.000000000"
+
+type Snapshot struct {
+	Path string
+	Time time.Time
+}
+
+type ConfigChange struct {
+	Key string
+	// nil when the key isn't set on that side
+	Old, New any
+}
+
+func Snapshots() ([]Snapshot, error) {
+	entries, err := os.ReadDir(configHistoryPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var snaps []Snapshot
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		t, err := time.ParseInLocation(snapshotLayout, strings.TrimPrefix(name, "config-"), time.UTC)
+		if err != nil {
+			continue
+		}
+		snaps = append(snaps, Snapshot{filepath.Join(configHistoryPath(), e.Name()), t})
+	}
+	// newest first
+	slices.SortFunc(snaps, func(a, b Snapshot) int {
+		return b.Time.Compare(a.Time)
+	})
+	return snaps, nil
+}
+
+// Copies the current config.json into the history and drops the oldest
+// snapshots past the limit.
+func snapshot(data []byte) error {
+	if err := os.MkdirAll(configHistoryPath(), 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("config-%s.json", time.Now().UTC().Format(snapshotLayout))
+	if err := writeFileAtomic(filepath.Join(configHistoryPath(), name), data, 0o644); err != nil {
+		return err
+	}
+	snaps, err := Snapshots()
+	if err != nil {
+		return err
+	}
+	for _, s := range snaps[min(len(snaps), max(Opts.ConfigHistory, 1)):] {
+		os.Remove(s.Path)
+	}
+	return nil
+}
+
+func LoadSnapshot(s Snapshot) (map[string]any, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	var c map[string]any
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(s.Path), err)
+	}
+	return c, nil
+}
+
+func flatten(prefix string, v any, out map[string]any) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		out[prefix] = v
+		return
+	}
+	for k, val := range m {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		flatten(k, val, out)
+	}
+}
+
+// Lists the keys of the "config" section that differ between a and b,
+// nested sections joined with dots.
+func DiffConfig(a, b map[string]any) []ConfigChange {
+	fa, fb := map[string]any{}, map[string]any{}
+	flatten("", a["config"], fa)
+	flatten("", b["config"], fb)
+	var changes []ConfigChange
+	keys := slices.Sorted(maps.Keys(fa))
+	for k := range fb {
+		if _, ok := fa[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		old, new := fa[k], fb[k]
+		if fmt.Sprint(old) != fmt.Sprint(new) {
+			changes = append(changes, ConfigChange{k, old, new})
+		}
+	}
+	return changes
+}
+
+// Makes s the current config, dropping any staged changes. The config it
+// replaces is snapshotted like on any other save, so a rollback can itself
+// be undone. Returns what changed.
+func RestoreSnapshot(s Snapshot) ([]ConfigChange, error) {
+	c, err := LoadSnapshot(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateConfig(c); err != nil {
+		return nil, err
+	}
+	if err := updateConfig(); err != nil {
+		return nil, err
+	}
+	changes := DiffConfig(config, c)
+	old := config
+	setSavedConfig(c)
+	if err := SaveConfigFile(); err != nil {
+		setSavedConfig(old)
+		return nil, err
+	}
+	DiscardChanges()
+	return changes, nil
+}
@@ -0,0 +1,290 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sshui.log in Opts.LogDir, moved to sshui.log.1 and so on once it gets big.
+// Warnings and errors are also kept in memory for the Diagnostics screen.
+
+const (
+	logName     = "sshui.log"
+	logMaxSize  = 1 << 20
+	logKeep     = 3
+	recentLimit = 200
+)
+
+type LogRecord struct {
+	Time      time.Time
+	Level     slog.Level
+	Component string
+	Message   string
+	// the other attributes, as key=value
+	Attrs string
+}
+
+func (r LogRecord) String() string {
+	s := fmt.Sprintf("%s %-5s", r.Time.Format("15:04:05"), r.Level)
+	if r.Component != "" {
+		s += " [" + r.Component + "]"
+	}
+	s += " " + r.Message
+	if r.Attrs != "" {
+		s += " " + r.Attrs
+	}
+	return s
+}
+
+var (
+	root    atomic.Pointer[slog.Handler]
+	recent  []LogRecord
+	recentM sync.Mutex
+)
+
+// A logger for one part of sshui. It can be made before InitLog, records
+// go wherever InitLog sends them, nowhere until then.
+func Logger(component string) *slog.Logger {
+	return slog.New(lateHandler{func(h slog.Handler) slog.Handler {
+		return h.WithAttrs([]slog.Attr{slog.String("component", component)})
+	}})
+}
+
+// Opens the log and makes it the destination of every Logger
+func InitLog() error {
+	if Opts.LogDir == "" {
+		Opts.LogDir = defaultLogDir()
+	}
+	if err := os.MkdirAll(Opts.LogDir, 0o755); err != nil {
+		return err
+	}
+	f, err := openRotating(LogFile())
+	if err != nil {
+		return err
+	}
+	var h slog.Handler = teeHandler{
+		slog.NewTextHandler(f, &slog.HandlerOptions{Level: Opts.LogLevel}),
+		ringHandler{},
+	}
+	root.Store(&h)
+	return nil
+}
+
+func LogFile() string {
+	return LogPath(logName)
+}
+
+// Warnings and errors, oldest first
+func RecentLogs() []LogRecord {
+	recentM.Lock()
+	defer recentM.Unlock()
+	return append([]LogRecord(nil), recent...)
+}
+
+func ClearRecentLogs() {
+	recentM.Lock()
+	defer recentM.Unlock()
+	recent = nil
+}
+
+// /var/log/sshui when we may write there, the XDG state directory otherwise
+func defaultLogDir() string {
+	if writableDir("/var/log/sshui") {
+		return "/var/log/sshui"
+	}
+	state := os.Getenv("XDG_STATE_HOME")
+	if state == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "."
+		}
+		state = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(state, "sshui")
+}
+
+func writableDir(dir string) bool {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false
+	}
+	f, err := os.CreateTemp(dir, ".sshui")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	size int64
+}
+
+func openRotating(path string) (*rotatingFile, error) {
+	r := &rotatingFile{path: path}
+	return r, r.open()
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, fi.Size()
+	return nil
+}
+
+// sshui.log.2 becomes .3 and so on, the oldest falls off
+func (r *rotatingFile) rotate() error {
+	r.f.Close()
+	for i := logKeep - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	os.Rename(r.path, r.path+".1")
+	return r.open()
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size+int64(len(p)) > logMaxSize && r.size > 0 {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Hands records to the handler InitLog set up, after with
+type lateHandler struct {
+	with func(slog.Handler) slog.Handler
+}
+
+func (h lateHandler) handler() slog.Handler {
+	r := root.Load()
+	if r == nil {
+		return nil
+	}
+	return h.with(*r)
+}
+
+func (h lateHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	r := root.Load()
+	return r != nil && (*r).Enabled(ctx, l)
+}
+
+func (h lateHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if hh := h.handler(); hh != nil {
+		return hh.Handle(ctx, rec)
+	}
+	return nil
+}
+
+func (h lateHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return lateHandler{func(hh slog.Handler) slog.Handler {
+		return h.with(hh).WithAttrs(attrs)
+	}}
+}
+
+func (h lateHandler) WithGroup(name string) slog.Handler {
+	return lateHandler{func(hh slog.Handler) slog.Handler {
+		return h.with(hh).WithGroup(name)
+	}}
+}
+
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, rec slog.Record) error {
+	var err error
+	for _, h := range t {
+		if h.Enabled(ctx, rec.Level) {
+			if e := h.Handle(ctx, rec.Clone()); e != nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hs := make(teeHandler, len(t))
+	for i, h := range t {
+		hs[i] = h.WithAttrs(attrs)
+	}
+	return hs
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	hs := make(teeHandler, len(t))
+	for i, h := range t {
+		hs[i] = h.WithGroup(name)
+	}
+	return hs
+}
+
+// Keeps warnings and errors for RecentLogs. Groups aren't used by sshui
+// and are flattened.
+type ringHandler struct {
+	attrs []slog.Attr
+}
+
+func (h ringHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= slog.LevelWarn
+}
+
+func (h ringHandler) Handle(_ context.Context, rec slog.Record) error {
+	lr := LogRecord{Time: rec.Time, Level: rec.Level, Message: rec.Message}
+	var attrs []string
+	add := func(a slog.Attr) bool {
+		if a.Key == "component" {
+			lr.Component = a.Value.String()
+		} else {
+			attrs = append(attrs, a.Key+"="+a.Value.String())
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	rec.Attrs(add)
+	lr.Attrs = strings.Join(attrs, " ")
+	recentM.Lock()
+	defer recentM.Unlock()
+	recent = append(recent, lr)
+	recent = recent[max(0, len(recent)-recentLimit):]
+	return nil
+}
+
+func (h ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return ringHandler{append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)}
+}
+
+func (h ringHandler) WithGroup(string) slog.Handler {
+	return h
+}
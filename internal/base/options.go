@@ -0,0 +1,182 @@
+package base
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Paths and endpoints sshui talks to. The defaults match the Nodo layout;
+// each option can be overridden in the settings file, by an SSHUI_*
+// environment variable or by a command-line flag, in increasing priority.
+type Options struct {
+	DaemonUrl    string
+	MoneropayUrl string
+	MoneropayDB  string
+	LwsAdmin     string
+	LwsDB        string
+	MoneroDir    string
+	ShellUser    string
+	ConfigPath   string
+	// number of config.json snapshots kept
+	ConfigHistory int
+	NodePort      int
+	LwsPort       int
+	// empty for /var/log/sshui or the XDG state directory
+	LogDir   string
+	LogLevel slog.Level
+	Demo     bool
+}
+
+var Opts = Options{
+	DaemonUrl:     "http://127.0.0.1:18081",
+	MoneropayUrl:  "http://127.0.0.1:5000",
+	MoneropayDB:   "/home/nodo/moneropay.sqlite",
+	LwsAdmin:      "/home/nodo/bin/monero-lws-admin",
+	LwsDB:         "/media/monero/bitmonero/light_wallet_server",
+	MoneroDir:     "/media/monero/bitmonero",
+	ShellUser:     "nodo",
+	ConfigPath:    "/home/nodo/variables/config.json",
+	ConfigHistory: 20,
+	NodePort:      18089,
+	LwsPort:       18089,
+	LogLevel:      slog.LevelInfo,
+}
+
+const defaultSettings = "/etc/sshui.toml"
+
+func newFlagSet(settings *string) *flag.FlagSet {
+	fs := flag.NewFlagSet("sshui", flag.ContinueOnError)
+	fs.StringVar(settings, "settings", defaultSettings, "settings file (flat TOML: key = value)")
+	fs.StringVar(&Opts.DaemonUrl, "daemon-url", Opts.DaemonUrl, "monerod RPC URL")
+	fs.StringVar(&Opts.MoneropayUrl, "moneropay-url", Opts.MoneropayUrl, "MoneroPay API URL")
+	fs.StringVar(&Opts.MoneropayDB, "moneropay-db", Opts.MoneropayDB, "MoneroPay sqlite database")
+	fs.StringVar(&Opts.LwsAdmin, "lws-admin", Opts.LwsAdmin, "monero-lws-admin binary")
+	fs.StringVar(&Opts.LwsDB, "lws-db", Opts.LwsDB, "monero-lws database directory")
+	fs.StringVar(&Opts.MoneroDir, "monero-dir", Opts.MoneroDir, "monero data directory")
+	fs.StringVar(&Opts.ShellUser, "shell-user", Opts.ShellUser, "user the Monero directory shell runs as")
+	fs.StringVar(&Opts.ConfigPath, "config", Opts.ConfigPath, "Nodo config.json")
+	fs.IntVar(&Opts.ConfigHistory, "config-history", Opts.ConfigHistory, "number of config.json snapshots to keep")
+	fs.IntVar(&Opts.NodePort, "node-port", Opts.NodePort, "public node RPC port shown to users")
+	fs.IntVar(&Opts.LwsPort, "lws-port", Opts.LwsPort, "light wallet server port shown to users")
+	fs.StringVar(&Opts.LogDir, "log-dir", Opts.LogDir, "directory for sshui.log and debug.log (default /var/log/sshui or $XDG_STATE_HOME/sshui)")
+	fs.TextVar(&Opts.LogLevel, "log-level", Opts.LogLevel, "least severe level logged: debug, info, warn or error")
+	fs.BoolVar(&Opts.Demo, "demo", Opts.Demo, "simulate the node instead of using the real services")
+	return fs
+}
+
+func envName(flag string) string {
+	return "SSHUI_" + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+func settingsKey(flag string) string {
+	return strings.ReplaceAll(flag, "-", "_")
+}
+
+// Reads top level key = value pairs. Tables, arrays and multi-line
+// strings are not needed for our options and are rejected.
+func readSettings(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	vals := map[string]string{}
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, n)
+		}
+		key = strings.TrimSpace(key)
+		val = strings.TrimSpace(val)
+		if strings.HasPrefix(val, `"`) || strings.HasPrefix(val, "'") {
+			end := strings.IndexByte(val[1:], val[0])
+			if end < 0 {
+				return nil, fmt.Errorf("%s:%d: unterminated string", path, n)
+			}
+			if val[0] == '"' {
+				val, err = strconv.Unquote(val[:end+2])
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+				}
+			} else {
+				val = val[1 : end+1]
+			}
+		} else if i := strings.IndexByte(val, '#'); i >= 0 {
+			val = strings.TrimSpace(val[:i])
+		}
+		vals[key] = val
+	}
+	return vals, sc.Err()
+}
+
+func ParseOptions(args []string) error {
+	var settings string
+	fs := newFlagSet(&settings)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	if v, ok := os.LookupEnv(envName("settings")); ok && !set["settings"] {
+		settings = v
+		set["settings"] = true
+	}
+	file, err := readSettings(settings)
+	if errors.Is(err, os.ErrNotExist) && !set["settings"] {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	for key := range file {
+		// only the key spelled the way it is read, daemon-url would be ignored
+		f := fs.Lookup(strings.ReplaceAll(key, "_", "-"))
+		if f == nil || f.Name == "settings" || key != settingsKey(f.Name) {
+			return fmt.Errorf("%s: unknown setting %q", settings, key)
+		}
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || set[f.Name] || f.Name == "settings" {
+			return
+		}
+		if v, ok := os.LookupEnv(envName(f.Name)); ok {
+			err = fs.Set(f.Name, v)
+		} else if v, ok := file[settingsKey(f.Name)]; ok {
+			err = fs.Set(f.Name, v)
+		}
+		if err != nil {
+			err = fmt.Errorf("%s: %v", f.Name, err)
+		}
+	})
+	return err
+}
+
+func configBackupPath() string {
+	return filepath.Join(filepath.Dir(Opts.ConfigPath), "config.back.json")
+}
+
+func configHistoryPath() string {
+	return filepath.Join(filepath.Dir(Opts.ConfigPath), "config.history")
+}
+
+func firstBootPath() string {
+	return filepath.Join(filepath.Dir(Opts.ConfigPath), "firstboot")
+}
+
+func LogPath(name string) string {
+	return filepath.Join(Opts.LogDir, name)
+}
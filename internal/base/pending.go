@@ -0,0 +1,150 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Settings are staged here and written together by ApplyChanges, so a
+// batch of edits costs one save and one round of restarts.
+var pending = map[string]any{}
+
+// How many keys PendingChanges would list. Kept up to date whenever
+// pending or the saved config changes, the status bar reads it on every
+// frame and shouldn't diff, let alone reload, the config each time.
+var pendingCount int
+
+// Services to restart when a key changes. Keys of a section not listed
+// themselves go by the section's name.
+var keyServices = map[string][]string{
+	"in_peers":        {"monerod"},
+	"out_peers":       {"monerod"},
+	"limit_rate_up":   {"monerod"},
+	"limit_rate_down": {"monerod"},
+	"rpc_enabled":     {"monerod"},
+	"rpcu":            {"monerod"},
+	"rpcp":            {"monerod"},
+	"anon_rpc":        {"monerod"},
+	"banlists":        {"monerod"},
+	// monerod's proxy flags follow these too
+	"tor_enabled":        {"tor", "monerod"},
+	"tor_global_enabled": {"tor", "monerod"},
+	"i2p_enabled":        {"i2pd", "monerod"},
+	"moneropay":          {"moneropay"},
+}
+
+// The services affected by changes, in the order they should be restarted
+func AffectedServices(changes []ConfigChange) []string {
+	var services []string
+	for _, c := range changes {
+		s, ok := keyServices[c.Key]
+		if !ok {
+			section, _, _ := strings.Cut(c.Key, ".")
+			s = keyServices[section]
+		}
+		for _, svc := range s {
+			if !slices.Contains(services, svc) {
+				services = append(services, svc)
+			}
+		}
+	}
+	return services
+}
+
+// Stores v the way it reads back from config.json, numbers as float64
+func normalize(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var n any
+	err = json.Unmarshal(data, &n)
+	return n, err
+}
+
+func setPath(c map[string]any, key string, v any) error {
+	sec, ok := c["config"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("config.json has no config section")
+	}
+	path := strings.Split(key, ".")
+	for _, k := range path[:len(path)-1] {
+		if sec, ok = sec[k].(map[string]any); !ok {
+			return fmt.Errorf("config.json has no %s section", k)
+		}
+	}
+	sec[path[len(path)-1]] = v
+	return nil
+}
+
+// A copy of the loaded config with the pending changes applied
+func stagedConfig() (map[string]any, error) {
+	var c map[string]any
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	for k, v := range pending {
+		if err := setPath(c, k, v); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func countPending() {
+	c, err := stagedConfig()
+	if err != nil {
+		pendingCount = 0
+		return
+	}
+	pendingCount = len(DiffConfig(config, c))
+}
+
+// The number of pending changes as of the last stage, save, discard or
+// reload. Cheap enough to call on every render.
+func PendingCount() int {
+	return pendingCount
+}
+
+func PendingChanges() []ConfigChange {
+	if err := updateConfig(); err != nil {
+		return nil
+	}
+	c, err := stagedConfig()
+	if err != nil {
+		return nil
+	}
+	return DiffConfig(config, c)
+}
+
+// Writes the pending changes in one save. Returns what changed.
+func ApplyChanges() ([]ConfigChange, error) {
+	if err := updateConfig(); err != nil {
+		return nil, err
+	}
+	c, err := stagedConfig()
+	if err != nil {
+		return nil, err
+	}
+	changes := DiffConfig(config, c)
+	old := config
+	setSavedConfig(c)
+	if err := SaveConfigFile(); err != nil {
+		setSavedConfig(old)
+		return nil, err
+	}
+	clear(pending)
+	pendingCount = 0
+	return changes, nil
+}
+
+func DiscardChanges() {
+	clear(pending)
+	pendingCount = 0
+}
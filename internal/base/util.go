@@ -2,31 +2,39 @@ package base
 
 import (
 	"encoding/json"
-	"io"
+	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"regexp"
+	"strings"
+	"sync/atomic"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/mergestat/timediff"
 )
 
 var (
-	Dump    io.Writer
-	config  map[string]any
-	lastUpd time.Time = time.Unix(0, 0)
+	logger = Logger("config")
+	config map[string]any
+	// set while WatchConfig keeps config up to date
+	watching atomic.Bool
 )
 
 const addrPattern = "^4[0-9A-Za-z]{94}$"
 
-const (
-	configLoc = "/home/nodo/variables/config.json"
-	configBak = "/home/nodo/variables/config.back.json"
-)
+// config.json was saved, the services in Services need a restart
+type ConfigSavedMsg struct {
+	Services []string
+}
 
-type ConfigSavedMsg struct{}
+// config.json was written, by sshui or anything else
+type ConfigFileMsg struct{}
+
+// Something other than sshui changed these keys in config.json
+type ConfigChangedMsg struct {
+	Changes []ConfigChange
+}
 
 type ErrorMsg struct {
 	err error
@@ -44,25 +52,28 @@ func GetBool(s string) bool {
 	return s == "TRUE"
 }
 
-func SaveConfigFile() tea.Msg {
-	err := backup()
+// Validates the loaded config and writes it, keeping the previous file as
+// a backup.
+func SaveConfigFile() error {
+	if config == nil {
+		return errors.New("no config loaded")
+	}
+	if err := validateConfig(config); err != nil {
+		return err
+	}
+	j, err := json.MarshalIndent(config, "", "\t")
 	if err != nil {
-		spew.Fprintf(Dump, "SaveConfig: %v", err)
 		return err
 	}
-	if config != nil {
-		j, err := json.MarshalIndent(config, "", "\t")
-		if err != nil {
-			return err
-		}
-		os.WriteFile(configLoc, j, 0o644)
+	if err := backup(); err != nil {
+		return fmt.Errorf("backup: %w", err)
 	}
-	return ConfigSavedMsg{}
+	return writeFileAtomic(Opts.ConfigPath, j, 0o644)
 }
 
 func loadConfigFile() (map[string]any, error) {
 	var j map[string]any
-	data, err := os.ReadFile(configLoc)
+	data, err := os.ReadFile(Opts.ConfigPath)
 	if err != nil {
 		return nil, err
 	}
@@ -74,112 +85,168 @@ func loadConfigFile() (map[string]any, error) {
 }
 
 func updateConfig() error {
-	if config == nil || time.Since(lastUpd) > 2*time.Second {
-		var err error
-		config, err = loadConfigFile()
+	if config == nil || !watching.Load() {
+		c, err := loadConfigFile()
 		if err != nil {
 			return err
 		}
+		setSavedConfig(c)
 	}
 	return nil
 }
 
-func SetMpayConfig(key string, value any) {
-	err := updateConfig()
-	if err != nil {
-		spew.Fdump(Dump, err)
-		return
-	}
-	if config["config"] == nil ||
-		config["config"].(map[string]any)["moneropay"] == nil {
-		return
+// The RPC login of the saved config. Staged changes don't count, monerod
+// only gets them once they are applied.
+type RPCLogin struct {
+	Enabled    bool
+	User, Pass string
+}
+
+var rpcLogin atomic.Pointer[RPCLogin]
+
+// Makes c the saved config. The UI goroutine is the only one touching
+// config, what others need of it is copied here.
+func setSavedConfig(c map[string]any) {
+	config = c
+	l := RPCLogin{}
+	l.Enabled = getVal(c, "rpc_enabled") == "TRUE"
+	l.User, _ = getVal(c, "rpcu").(string)
+	l.Pass, _ = getVal(c, "rpcp").(string)
+	rpcLogin.Store(&l)
+}
+
+// Safe to call from any goroutine
+func SavedRPCLogin() RPCLogin {
+	if l := rpcLogin.Load(); l != nil {
+		return *l
 	}
-	config["config"].(map[string]any)["moneropay"].(map[string]any)[key] = value
-	SaveConfigFile()
+	return RPCLogin{}
 }
 
-func SetBanlistConfig(key string, value bool) {
-	err := updateConfig()
+// Rereads config.json after a ConfigFileMsg and returns the keys that
+// differ from the loaded config, none if it was sshui's own save.
+func ReloadConfig() ([]ConfigChange, error) {
+	c, err := loadConfigFile()
 	if err != nil {
-		spew.Fdump(Dump, err)
-		return
-	}
-	if config["config"] == nil ||
-		config["config"].(map[string]any)["banlists"] == nil {
-		return
+		return nil, err
 	}
-	config["config"].(map[string]any)["banlists"].(map[string]any)[key] = Bool(value)
-	SaveConfigFile()
+	changes := DiffConfig(config, c)
+	setSavedConfig(c)
+	// what was staged may be what the file says now
+	countPending()
+	return changes, nil
 }
 
-func SetConfig(key string, value any) {
-	err := updateConfig()
+// Stages value for the key at path below the "config" section. It is
+// checked against the rest of the staged config right away and written by
+// ApplyChanges.
+func setConfigValue(value any, path ...string) error {
+	if err := updateConfig(); err != nil {
+		return err
+	}
+	key := strings.Join(path, ".")
+	v, err := normalize(value)
 	if err != nil {
-		spew.Fdump(Dump, err)
-		return
+		return err
 	}
-	if config["config"] == nil {
-		return
+	old, staged := pending[key]
+	pending[key] = v
+	c, err := stagedConfig()
+	if err == nil {
+		err = validateConfig(c)
 	}
-	switch value := value.(type) {
-	case bool:
-		config["config"].(map[string]any)[key] = Bool(value)
-	default:
-		config["config"].(map[string]any)[key] = value
+	if err != nil {
+		// flag errors come back without the key they were found at
+		var cve *ConfigValueErr
+		if errors.As(err, &cve) && cve.Key == "" {
+			cve.Key = key
+		}
+		if staged {
+			pending[key] = old
+		} else {
+			delete(pending, key)
+		}
+		return err
+	}
+	// setting a value back to what is saved leaves nothing to apply
+	if saved := getVal(config, path...); fmt.Sprint(saved) == fmt.Sprint(v) {
+		delete(pending, key)
 	}
-	SaveConfigFile()
+	countPending()
+	return nil
+}
+
+func SetMpayConfig(key string, value any) error {
+	return setConfigValue(value, "moneropay", key)
+}
+
+func SetBanlistConfig(key string, value bool) error {
+	return setConfigValue(Bool(value), "banlists", key)
+}
+
+func SetConfig(key string, value any) error {
+	if b, ok := value.(bool); ok {
+		value = Bool(b)
+	}
+	return setConfigValue(value, key)
 }
 
+// Reads config.json. Called once at start, before the goroutines that
+// read SavedRPCLogin are started.
+func LoadConfig() error {
+	return updateConfig()
+}
+
+// For the UI goroutine only, it may reload config
 func GetConfig() *(map[string]any) {
 	err := updateConfig()
 	if err != nil {
-		spew.Fdump(Dump, err)
+		logger.Error("reading config failed", "path", Opts.ConfigPath, "err", err)
 	}
 	return &config
 }
 
-func GetVal(qr ...string) any {
-	c := (*GetConfig())["config"].(map[string]any)
+func getVal(c map[string]any, qr ...string) any {
+	c, _ = c["config"].(map[string]any)
 	for _, s := range qr {
 		switch c[s].(type) {
 		case map[string]any:
 			c = c[s].(map[string]any)
 		default:
-			if c[s] == "TRUE" || c[s] == "FALSE" {
-				return GetBool(c[s].(string))
-			}
 			return c[s]
 		}
 	}
 	return c
 }
 
+// Reads a value below the "config" section, staged changes included.
+// Flags are returned as bool.
+func GetVal(qr ...string) any {
+	v, ok := pending[strings.Join(qr, ".")]
+	if !ok {
+		v = getVal(*GetConfig(), qr...)
+	}
+	if v == "TRUE" || v == "FALSE" {
+		return GetBool(v.(string))
+	}
+	return v
+}
+
 func IsFirstBoot() bool {
-	_, err := os.Stat("/home/nodo/variables/firstboot")
+	_, err := os.Stat(firstBootPath())
 	err, ok := err.(*fs.PathError)
 	return ok
 }
 
 func backup() error {
-	_, err := os.Stat(configBak)
-	if err != nil {
-		os.Remove(configBak)
-	}
-	from, err := os.Open(configLoc)
-	if err != nil {
-		return err
-	}
-	defer from.Close()
-	to, err := os.Create(configBak)
+	data, err := os.ReadFile(Opts.ConfigPath)
 	if err != nil {
 		return err
 	}
-	defer to.Close()
-	_, err = io.Copy(to, from)
-	if err != nil {
+	if err := snapshot(data); err != nil {
 		return err
 	}
-	return nil
+	return writeFileAtomic(configBackupPath(), data, 0o644)
 }
 
 func UnixTimeRelative(unix int64) string {
@@ -193,6 +260,33 @@ func UnixTime(unix int64) string {
 	return t.In(loc).Format("2 January 2006 15:04:05")
 }
 
+func FormatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+func FormatSeconds(sec uint64) string {
+	d := time.Duration(sec) * time.Second
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", d/(24*time.Hour), (d%(24*time.Hour))/time.Hour)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", d/time.Hour, (d%time.Hour)/time.Minute)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm %ds", d/time.Minute, (d%time.Minute)/time.Second)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
+
 func ValidateAddr(addr string) bool {
 	match, _ := regexp.MatchString(addrPattern, addr)
 	return match
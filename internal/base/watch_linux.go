@@ -0,0 +1,53 @@
+package base
+
+import (
+	"bytes"
+	"path/filepath"
+	"unsafe"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/sys/unix"
+)
+
+// Watches the directory holding config.json, so atomic replacements (a new
+// file renamed over it) are seen as well as writes in place. Every time the
+// file changes prog is sent a ConfigFileMsg.
+func WatchConfig(prog *tea.Program) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC)
+	if err != nil {
+		logger.Error("inotify failed, config changes won't be seen", "err", err)
+		return
+	}
+	defer unix.Close(fd)
+	dir, name := filepath.Split(Opts.ConfigPath)
+	if dir == "" {
+		dir = "."
+	}
+	if _, err := unix.InotifyAddWatch(fd, dir, unix.IN_CLOSE_WRITE|unix.IN_MOVED_TO); err != nil {
+		logger.Error("watching config failed", "dir", dir, "err", err)
+		return
+	}
+	watching.Store(true)
+	defer watching.Store(false)
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := unix.Read(fd, buf)
+		if err == unix.EINTR {
+			continue
+		} else if err != nil {
+			logger.Error("reading inotify events failed", "err", err)
+			return
+		}
+		changed := false
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			off += unix.SizeofInotifyEvent
+			evName := string(bytes.TrimRight(buf[off:off+int(ev.Len)], "\x00"))
+			off += int(ev.Len)
+			changed = changed || evName == name
+		}
+		if changed {
+			prog.Send(ConfigFileMsg{})
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package base
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// Without inotify the config is reloaded whenever it is read instead
+func WatchConfig(prog *tea.Program) {}
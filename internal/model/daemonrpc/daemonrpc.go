@@ -1,16 +1,79 @@
 package daemonrpc
 
-type DaemonRPCMsg struct {
-	Response DaemonRPCResponseWrapper
-}
+import (
+	"encoding/json"
+	"fmt"
+)
 
-type DaemonRPCResponseWrapper any
+// Result of a daemon call as delivered to the screens
+type DaemonRPCMsg[T any] struct {
+	Result *T
+	Err    error
+}
 
 type DaemonRPCResponse struct {
-	Jsonrpc string `json:"jsonrpc"`
-	Id      uint64 `json:"id"`
-	Result  any    `json:"result"`
-	Error   any    `json:"error"`
+	Jsonrpc string          `json:"jsonrpc"`
+	Id      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *DaemonRPCErr   `json:"error"`
+}
+
+type DaemonConnectionRefusedErr struct {
+	Err error
+}
+type DaemonTimeoutErr struct {
+	Err error
+}
+type DaemonHTTPStatusErr struct {
+	StatusCode int
+	Status     string
+}
+type DaemonDecodeErr struct {
+	Err error
+}
+type DaemonRPCErr struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+type DaemonStatusErr struct {
+	Status string
+}
+
+func (e *DaemonConnectionRefusedErr) Error() string {
+	return "connection refused"
+}
+
+func (e *DaemonConnectionRefusedErr) Unwrap() error {
+	return e.Err
+}
+
+func (e *DaemonTimeoutErr) Error() string {
+	return "timed out"
+}
+
+func (e *DaemonTimeoutErr) Unwrap() error {
+	return e.Err
+}
+
+func (e *DaemonHTTPStatusErr) Error() string {
+	return fmt.Sprintf("HTTP %s", e.Status)
+}
+
+func (e *DaemonDecodeErr) Error() string {
+	return fmt.Sprintf("invalid response: %v", e.Err)
+}
+
+func (e *DaemonDecodeErr) Unwrap() error {
+	return e.Err
+}
+
+func (e *DaemonRPCErr) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+}
+
+// Non-OK status strings such as BUSY while the daemon is syncing
+func (e *DaemonStatusErr) Error() string {
+	return fmt.Sprintf("status %s", e.Status)
 }
 
 type DaemonRPCRequest struct {
@@ -20,6 +83,8 @@ type DaemonRPCRequest struct {
 	Params  any    `json:"params"`
 }
 
+type NoParams struct{}
+
 type GetVersionHardForks struct {
 	Height    uint32 `json:"height"`
 	HfVersion uint   `json:"hf_version"`
@@ -79,9 +144,206 @@ type DaemonResponseBodyGetInfo struct {
 	WideDifficulty            string `json:"wide_difficulty"`
 }
 
-func MakeDaemonRPCResponse(response DaemonRPCResponseWrapper) *DaemonRPCResponse {
-	resp := &DaemonRPCResponse{
-		Result: response,
-	}
-	return resp
+type GetConnectionsConnection struct {
+	Address           string `json:"address"`
+	AddressType       int    `json:"address_type"`
+	AvgDownload       uint64 `json:"avg_download"`
+	AvgUpload         uint64 `json:"avg_upload"`
+	ConnectionId      string `json:"connection_id"`
+	CurrentDownload   uint64 `json:"current_download"`
+	CurrentUpload     uint64 `json:"current_upload"`
+	Height            uint64 `json:"height"`
+	Host              string `json:"host"`
+	Incoming          bool   `json:"incoming"`
+	Ip                string `json:"ip"`
+	LiveTime          uint64 `json:"live_time"`
+	LocalIp           bool   `json:"local_ip"`
+	Localhost         bool   `json:"localhost"`
+	PeerId            string `json:"peer_id"`
+	Port              string `json:"port"`
+	PruningSeed       uint32 `json:"pruning_seed"`
+	RecvCount         uint64 `json:"recv_count"`
+	RecvIdleTime      uint64 `json:"recv_idle_time"`
+	RpcCreditsPerHash uint32 `json:"rpc_credits_per_hash"`
+	RpcPort           uint16 `json:"rpc_port"`
+	SendCount         uint64 `json:"send_count"`
+	SendIdleTime      uint64 `json:"send_idle_time"`
+	State             string `json:"state"`
+	SupportFlags      uint32 `json:"support_flags"`
+}
+
+type DaemonResponseBodyGetConnections struct {
+	Connections []GetConnectionsConnection `json:"connections"`
+	Status      string                     `json:"status"`
+	Untrusted   bool                       `json:"untrusted"`
+}
+
+type GetBansBan struct {
+	Host    string `json:"host"`
+	Ip      uint32 `json:"ip"`
+	Seconds uint32 `json:"seconds"`
+}
+
+type DaemonResponseBodyGetBans struct {
+	Bans      []GetBansBan `json:"bans"`
+	Status    string       `json:"status"`
+	Untrusted bool         `json:"untrusted"`
+}
+
+type SetBansBan struct {
+	Host    string `json:"host"`
+	Ban     bool   `json:"ban"`
+	Seconds uint32 `json:"seconds"`
+}
+
+type DaemonRequestParamsSetBans struct {
+	Bans []SetBansBan `json:"bans"`
+}
+
+type DaemonResponseBodySetBans struct {
+	Status    string `json:"status"`
+	Untrusted bool   `json:"untrusted"`
+}
+
+type BlockHeader struct {
+	BlockSize            uint64 `json:"block_size"`
+	BlockWeight          uint64 `json:"block_weight"`
+	CumulativeDifficulty uint64 `json:"cumulative_difficulty"`
+	Depth                uint64 `json:"depth"`
+	Difficulty           uint64 `json:"difficulty"`
+	Hash                 string `json:"hash"`
+	Height               uint64 `json:"height"`
+	LongTermWeight       uint64 `json:"long_term_weight"`
+	MajorVersion         uint   `json:"major_version"`
+	MinerTxHash          string `json:"miner_tx_hash"`
+	MinorVersion         uint   `json:"minor_version"`
+	Nonce                uint32 `json:"nonce"`
+	NumTxes              uint   `json:"num_txes"`
+	OrphanStatus         bool   `json:"orphan_status"`
+	PowHash              string `json:"pow_hash"`
+	PrevHash             string `json:"prev_hash"`
+	Reward               uint64 `json:"reward"`
+	Timestamp            int64  `json:"timestamp"`
+	WideDifficulty       string `json:"wide_difficulty"`
+}
+
+type DaemonResponseBodyGetLastBlockHeader struct {
+	BlockHeader BlockHeader `json:"block_header"`
+	Status      string      `json:"status"`
+	Untrusted   bool        `json:"untrusted"`
+}
+
+type DaemonRequestParamsGetBlockHeadersRange struct {
+	StartHeight uint64 `json:"start_height"`
+	EndHeight   uint64 `json:"end_height"`
+}
+
+type DaemonResponseBodyGetBlockHeadersRange struct {
+	Headers   []BlockHeader `json:"headers"`
+	Status    string        `json:"status"`
+	Untrusted bool          `json:"untrusted"`
+}
+
+type DaemonRequestParamsGetBlockHeaderByHeight struct {
+	Height uint64 `json:"height"`
+}
+
+type DaemonRequestParamsGetBlockHeaderByHash struct {
+	Hash string `json:"hash"`
+}
+
+// Response to get_block_header_by_height and get_block_header_by_hash
+type DaemonResponseBodyGetBlockHeader struct {
+	BlockHeader BlockHeader `json:"block_header"`
+	Status      string      `json:"status"`
+	Untrusted   bool        `json:"untrusted"`
+}
+
+type TxPoolHisto struct {
+	Txs   uint32 `json:"txs"`
+	Bytes uint64 `json:"bytes"`
+}
+
+type TxPoolStats struct {
+	BytesMax        uint32        `json:"bytes_max"`
+	BytesMed        uint32        `json:"bytes_med"`
+	BytesMin        uint32        `json:"bytes_min"`
+	BytesTotal      uint64        `json:"bytes_total"`
+	FeeTotal        uint64        `json:"fee_total"`
+	Histo           []TxPoolHisto `json:"histo"`
+	Histo98pc       uint64        `json:"histo_98pc"`
+	Num10m          uint32        `json:"num_10m"`
+	NumDoubleSpends uint32        `json:"num_double_spends"`
+	NumFailing      uint32        `json:"num_failing"`
+	NumNotRelayed   uint32        `json:"num_not_relayed"`
+	Oldest          int64         `json:"oldest"`
+	TxsTotal        uint32        `json:"txs_total"`
+}
+
+type DaemonResponseBodyGetTransactionPoolStats struct {
+	PoolStats TxPoolStats `json:"pool_stats"`
+	Status    string      `json:"status"`
+	Untrusted bool        `json:"untrusted"`
+}
+
+type TxPoolTransaction struct {
+	BlobSize           uint64 `json:"blob_size"`
+	DoNotRelay         bool   `json:"do_not_relay"`
+	DoubleSpendSeen    bool   `json:"double_spend_seen"`
+	Fee                uint64 `json:"fee"`
+	IdHash             string `json:"id_hash"`
+	KeptByBlock        bool   `json:"kept_by_block"`
+	LastFailedHeight   uint64 `json:"last_failed_height"`
+	LastFailedIdHash   string `json:"last_failed_id_hash"`
+	LastRelayedTime    int64  `json:"last_relayed_time"`
+	MaxUsedBlockHeight uint64 `json:"max_used_block_height"`
+	ReceiveTime        int64  `json:"receive_time"`
+	Relayed            bool   `json:"relayed"`
+	Weight             uint64 `json:"weight"`
+}
+
+type DaemonResponseBodyGetTransactionPool struct {
+	Transactions []TxPoolTransaction `json:"transactions"`
+	Status       string              `json:"status"`
+	Untrusted    bool                `json:"untrusted"`
+}
+
+type DaemonRequestParamsFlushTxpool struct {
+	Txids []string `json:"txids,omitempty"`
+}
+
+type DaemonResponseBodyFlushTxpool struct {
+	Status string `json:"status"`
+}
+
+type DaemonRequestParamsGetPeerList struct {
+	PublicOnly bool `json:"public_only"`
+}
+
+type PeerListPeer struct {
+	Host              string `json:"host"`
+	Id                uint64 `json:"id"`
+	Ip                uint32 `json:"ip"`
+	LastSeen          int64  `json:"last_seen"`
+	Port              uint16 `json:"port"`
+	PruningSeed       uint32 `json:"pruning_seed"`
+	RpcPort           uint16 `json:"rpc_port"`
+	RpcCreditsPerHash uint32 `json:"rpc_credits_per_hash"`
+}
+
+type DaemonResponseBodyGetPeerList struct {
+	GrayList  []PeerListPeer `json:"gray_list"`
+	WhiteList []PeerListPeer `json:"white_list"`
+	Status    string         `json:"status"`
+	Untrusted bool           `json:"untrusted"`
+}
+
+type DaemonResponseBodyGetNetStats struct {
+	StartTime       int64  `json:"start_time"`
+	TotalBytesIn    uint64 `json:"total_bytes_in"`
+	TotalBytesOut   uint64 `json:"total_bytes_out"`
+	TotalPacketsIn  uint64 `json:"total_packets_in"`
+	TotalPacketsOut uint64 `json:"total_packets_out"`
+	Status          string `json:"status"`
+	Untrusted       bool   `json:"untrusted"`
 }
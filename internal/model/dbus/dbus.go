@@ -6,6 +6,19 @@ type DbusSignalMsg struct {
 
 type DbusSignal any
 
+// Whether the embedded interface can be heard from, and why not
+type BusStatusMsg struct {
+	Online bool
+	Err    error
+}
+
+// Result of a call to the embedded interface as delivered to the screens
+type DbusCallMsg struct {
+	Method string
+	Result any
+	Err    error
+}
+
 type StartRecoveryNotification struct {
 	Message string
 }
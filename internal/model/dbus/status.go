@@ -0,0 +1,165 @@
+package dbus
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The status reports of the embedded interface have one field per line,
+// either "key:value" or, for the hardware report, a bare value whose line
+// number says what it is. Unknown keys and extra lines are ignored so the
+// interface can grow without breaking sshui.
+
+var ErrMissing = errors.New("missing")
+
+// A field of a status report that was missing or couldn't be parsed
+type FieldError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	if e.Err == ErrMissing {
+		return e.Field + ": missing"
+	}
+	return fmt.Sprintf("%s: %q: %v", e.Field, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+type HardwareStatus struct {
+	// percent
+	CPUUsage float64
+	// GHz
+	CPUClock float64
+	// GB
+	RAMUsed, RAMTotal float64
+	// °C
+	Temperature float64
+	// blockchain drive, TB
+	SSDUsed, SSDTotal float64
+	// system storage, GB
+	EMMCUsed, EMMCTotal float64
+	Uptime              string
+}
+
+type ServiceStatus struct {
+	Monerod   string
+	Tor       string
+	I2pd      string
+	MoneroLws string
+	Sshd      string
+	Moneropay string
+}
+
+var statusKey = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+type statusField struct {
+	key string
+	set func(v string) error
+}
+
+func float(dst *float64) func(string) error {
+	return func(v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err.(*strconv.NumError).Err
+		}
+		*dst = f
+		return nil
+	}
+}
+
+func text(dst *string) func(string) error {
+	return func(v string) error {
+		if v == "" {
+			return errors.New("empty")
+		}
+		*dst = v
+		return nil
+	}
+}
+
+// Sets fields from msg. A line is matched to a field by its key, or by its
+// position when positional is true and it has no key. Returns every field
+// error joined.
+func parseStatus(msg string, fields []statusField, positional bool) error {
+	byKey := map[string]statusField{}
+	for _, f := range fields {
+		byKey[f.key] = f
+	}
+	seen := map[string]bool{}
+	var errs []error
+	// positions count the lines with something on them
+	i := -1
+	for _, line := range strings.Split(strings.TrimRight(msg, "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		i++
+		var (
+			f  statusField
+			ok bool
+			v  = line
+		)
+		if k, val, found := strings.Cut(line, ":"); found && statusKey.MatchString(strings.TrimSpace(k)) {
+			f, ok = byKey[strings.ToLower(strings.TrimSpace(k))]
+			v = strings.TrimSpace(val)
+		} else if positional && i < len(fields) {
+			f, ok = fields[i], true
+		}
+		if !ok || seen[f.key] {
+			continue
+		}
+		seen[f.key] = true
+		if err := f.set(v); err != nil {
+			errs = append(errs, &FieldError{f.key, v, err})
+		}
+	}
+	for _, f := range fields {
+		if !seen[f.key] {
+			errs = append(errs, &FieldError{Field: f.key, Err: ErrMissing})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Parses the body of a HardwareStatusReadyNotification. Fields that parsed
+// are set even when others didn't.
+func ParseHardwareStatus(msg string) (HardwareStatus, error) {
+	var s HardwareStatus
+	err := parseStatus(msg, []statusField{
+		{"cpu_usage", float(&s.CPUUsage)},
+		{"cpu_clock", float(&s.CPUClock)},
+		{"ram_used", float(&s.RAMUsed)},
+		{"ram_total", float(&s.RAMTotal)},
+		{"temperature", float(&s.Temperature)},
+		{"ssd_used", float(&s.SSDUsed)},
+		{"ssd_total", float(&s.SSDTotal)},
+		{"emmc_used", float(&s.EMMCUsed)},
+		{"emmc_total", float(&s.EMMCTotal)},
+		{"uptime", text(&s.Uptime)},
+	}, true)
+	return s, err
+}
+
+// Parses the body of a ServiceStatusReadyNotification, unit:state lines
+func ParseServiceStatus(msg string) (ServiceStatus, error) {
+	var s ServiceStatus
+	err := parseStatus(msg, []statusField{
+		{"monerod", text(&s.Monerod)},
+		{"tor", text(&s.Tor)},
+		{"i2pd", text(&s.I2pd)},
+		{"monero-lws", text(&s.MoneroLws)},
+		{"sshd", text(&s.Sshd)},
+		{"moneropay", text(&s.Moneropay)},
+	}, false)
+	return s, err
+}
@@ -0,0 +1,192 @@
+package dbus
+
+import (
+	"errors"
+	"testing"
+)
+
+// As the Nodo service sends it, one bare value per line
+const hardwareSample = `23.5
+1.8
+3.41
+7.76
+51.2
+0.245
+3.638
+9.4
+58.2
+3 days, 4:17
+`
+
+func fieldErrors(err error) map[string]*FieldError {
+	got := map[string]*FieldError{}
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, e := range joined.Unwrap() {
+			var fe *FieldError
+			if errors.As(e, &fe) {
+				got[fe.Field] = fe
+			}
+		}
+	}
+	return got
+}
+
+func TestParseHardwareStatus(t *testing.T) {
+	full := HardwareStatus{
+		CPUUsage: 23.5, CPUClock: 1.8, RAMUsed: 3.41, RAMTotal: 7.76,
+		Temperature: 51.2, SSDUsed: 0.245, SSDTotal: 3.638,
+		EMMCUsed: 9.4, EMMCTotal: 58.2, Uptime: "3 days, 4:17",
+	}
+	tests := []struct {
+		name string
+		msg  string
+		want HardwareStatus
+		// fields expected to fail and whether for being missing
+		errs map[string]bool
+	}{
+		{
+			name: "positional",
+			msg:  hardwareSample,
+			want: full,
+		},
+		{
+			name: "keyed",
+			msg: "cpu_usage:23.5\ncpu_clock:1.8\nram_used:3.41\nram_total:7.76\n" +
+				"temperature:51.2\nssd_used:0.245\nssd_total:3.638\n" +
+				"emmc_used:9.4\nemmc_total:58.2\nuptime: 3 days, 4:17\n",
+			want: full,
+		},
+		{
+			name: "keyed reordered with unknown fields",
+			msg: "uptime:3 days, 4:17\nfan_rpm:1200\nemmc_total:58.2\nemmc_used:9.4\n" +
+				"ssd_total:3.638\nssd_used:0.245\ntemperature:51.2\nram_total:7.76\n" +
+				"ram_used:3.41\nCPU_CLOCK:1.8\ncpu_usage : 23.5\ngpu:none\n",
+			want: full,
+		},
+		{
+			name: "blank lines and extra positional lines",
+			msg:  "\n" + hardwareSample + "\nextra\nlines\n",
+			want: full,
+		},
+		{
+			name: "short uptime",
+			msg:  "23.5\n1.8\n3.41\n7.76\n51.2\n0.245\n3.638\n9.4\n58.2\n0:05\n",
+			want: HardwareStatus{
+				CPUUsage: 23.5, CPUClock: 1.8, RAMUsed: 3.41, RAMTotal: 7.76,
+				Temperature: 51.2, SSDUsed: 0.245, SSDTotal: 3.638,
+				EMMCUsed: 9.4, EMMCTotal: 58.2, Uptime: "0:05",
+			},
+		},
+		{
+			name: "missing lines",
+			msg:  "23.5\n1.8\n3.41\n",
+			want: HardwareStatus{CPUUsage: 23.5, CPUClock: 1.8, RAMUsed: 3.41},
+			errs: map[string]bool{
+				"ram_total": true, "temperature": true, "ssd_used": true, "ssd_total": true,
+				"emmc_used": true, "emmc_total": true, "uptime": true,
+			},
+		},
+		{
+			name: "non-numeric values",
+			msg:  "n/a\n1.8\n3.41\n7.76\nhot\n0.245\n3.638\n9.4\n58.2\n3 days, 4:17\n",
+			want: HardwareStatus{
+				CPUClock: 1.8, RAMUsed: 3.41, RAMTotal: 7.76,
+				SSDUsed: 0.245, SSDTotal: 3.638,
+				EMMCUsed: 9.4, EMMCTotal: 58.2, Uptime: "3 days, 4:17",
+			},
+			errs: map[string]bool{"cpu_usage": false, "temperature": false},
+		},
+		{
+			name: "empty",
+			msg:  "",
+			errs: map[string]bool{
+				"cpu_usage": true, "cpu_clock": true, "ram_used": true, "ram_total": true,
+				"temperature": true, "ssd_used": true, "ssd_total": true,
+				"emmc_used": true, "emmc_total": true, "uptime": true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHardwareStatus(tt.msg)
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			checkFieldErrors(t, err, tt.errs)
+		})
+	}
+}
+
+func TestParseServiceStatus(t *testing.T) {
+	full := ServiceStatus{
+		Monerod: "active", Tor: "active", I2pd: "inactive",
+		MoneroLws: "active", Sshd: "active", Moneropay: "failed",
+	}
+	tests := []struct {
+		name string
+		msg  string
+		want ServiceStatus
+		errs map[string]bool
+	}{
+		{
+			name: "sample",
+			msg:  "monerod:active\ntor:active\ni2pd:inactive\nmonero-lws:active\nsshd:active\nmoneropay:failed\n",
+			want: full,
+		},
+		{
+			name: "reordered with unknown units",
+			msg:  "moneropay: failed\nnginx:active\nsshd:active\nmonero-lws:active\ni2pd:inactive\ntor:active\nmonerod:active",
+			want: full,
+		},
+		{
+			name: "positional lines are not services",
+			msg:  "active\nactive\n",
+			errs: map[string]bool{
+				"monerod": true, "tor": true, "i2pd": true,
+				"monero-lws": true, "sshd": true, "moneropay": true,
+			},
+		},
+		{
+			name: "missing and empty",
+			msg:  "monerod:active\ntor:\n",
+			want: ServiceStatus{Monerod: "active"},
+			errs: map[string]bool{
+				"tor": false, "i2pd": true, "monero-lws": true, "sshd": true, "moneropay": true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseServiceStatus(tt.msg)
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			checkFieldErrors(t, err, tt.errs)
+		})
+	}
+}
+
+func checkFieldErrors(t *testing.T, err error, want map[string]bool) {
+	t.Helper()
+	if len(want) == 0 {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		return
+	}
+	got := fieldErrors(err)
+	if len(got) != len(want) {
+		t.Errorf("got errors %v, want ones for %v", err, want)
+	}
+	for field, missing := range want {
+		fe, ok := got[field]
+		if !ok {
+			t.Errorf("no error for %s", field)
+			continue
+		}
+		if errors.Is(fe, ErrMissing) != missing {
+			t.Errorf("%s: got %v, missing %v", field, fe, missing)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package screens
+
+import (
+	"context"
+
+	"github.com/moneronodo/sshui/internal/backend/daemonrpc"
+	"github.com/moneronodo/sshui/internal/backend/i_moneropay"
+	"github.com/moneronodo/sshui/internal/backend/i_systemd"
+	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/model/lws"
+	"github.com/moneronodo/sshui/internal/model/moneropay"
+)
+
+// The services the screens talk to. The real ones live in internal/backend,
+// anything else with the same methods can stand in for them.
+
+// Screens log under the component of the service they were talking to
+var (
+	dbusLog    = base.Logger("dbus")
+	lwsLog     = base.Logger("lws")
+	mpayLog    = base.Logger("mpay")
+	configLog  = base.Logger("config")
+	systemdLog = base.Logger("systemd")
+	uiLog      = base.Logger("ui")
+)
+
+type DaemonClient interface {
+	Do(ctx context.Context, b *daemonrpc.Batch)
+}
+
+type SystemBus interface {
+	Call(ctx context.Context, method string, args ...any) ([]any, error)
+}
+
+type LWSAdmin interface {
+	ListAccounts() (lws.LwsListAccounts, error)
+	AddAccount(address, viewkey string) error
+	DeleteAccount(address string) error
+	DeactivateAccount(address string) error
+	ReactivateAccount(address string) error
+	Rescan(address string, height int) error
+}
+
+type MoneropayStore interface {
+	Health() moneropay.MoneropayHealth
+	TxList() []i_moneropay.Transaction
+	Receive(address string) (moneropay.MoneropayReceive, error)
+}
+
+type ServiceManager interface {
+	Start(ctx context.Context, unit string) error
+	Stop(ctx context.Context, unit string) error
+	Restart(ctx context.Context, unit string) error
+	Enable(ctx context.Context, unit string) error
+	Disable(ctx context.Context, unit string) error
+	Status(ctx context.Context, unit string) (i_systemd.UnitStatus, error)
+	Entries(unit string, lines, priority int) ([]i_systemd.JournalEntry, error)
+	Journal(unit string, lines int) ([]string, error)
+}
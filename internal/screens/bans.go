@@ -0,0 +1,238 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/backend/daemonrpc"
+	"github.com/moneronodo/sshui/internal/base"
+	rpc_model "github.com/moneronodo/sshui/internal/model/daemonrpc"
+)
+
+var bans *Bans = &Bans{}
+
+// The longest ban that can be set, well within what set_bans takes
+const banMaxHours = 365 * 24
+
+var (
+	bansPane   *ScreenPane
+	banAddPane *ScreenPane
+
+	banHostInput     *ScreenInputField
+	banDurationInput *ScreenInputField
+	banAddButton     *ScreenButton
+
+	banButtons map[string]*ScreenButton
+)
+
+// A ban set or lifted
+type BanSetMsg struct {
+	Host string
+	Err  error
+}
+
+type Bans struct {
+	init    bool
+	daemon  DaemonClient
+	bans    []rpc_model.GetBansBan
+	items   []ScreenItem
+	current int
+}
+
+func NewBans(daemon DaemonClient) *Bans {
+	bans.daemon = daemon
+	return bans
+}
+
+func (s *Bans) fetchBans() tea.Msg {
+	return daemonrpc.CallMsg(context.Background(), s.daemon, daemonrpc.GetBans, rpc_model.NoParams{})
+}
+
+func (s *Bans) setBan(host string, ban bool, seconds uint32) tea.Cmd {
+	daemon := s.daemon
+	return func() tea.Msg {
+		_, err := daemonrpc.Call(context.Background(), daemon, daemonrpc.SetBans,
+			rpc_model.DaemonRequestParamsSetBans{
+				Bans: []rpc_model.SetBansBan{{
+					Host:    host,
+					Ban:     ban,
+					Seconds: seconds,
+				}},
+			})
+		return BanSetMsg{host, err}
+	}
+}
+
+func validBanHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(host)
+	return err == nil
+}
+
+func (s *Bans) Init() tea.Msg {
+	banButtons = map[string]*ScreenButton{}
+	banHostInput = NewScreenInputField("", "IP or subnet (1.2.3.0/24)", gss.Color(base.CWhite))
+	banDurationInput = NewScreenInputField("", "Duration in hours", gss.Color(base.CWhite))
+	banDurationInput.Delegate.SetValue("24")
+	banAddButton = NewScreenButton("Ban", gss.Color(base.CBrightRed),
+		func(sb *ScreenButton) tea.Cmd {
+			host := banHostInput.Delegate.Value()
+			hours, err := strconv.Atoi(banDurationInput.Delegate.Value())
+			if !validBanHost(host) {
+				AddPopup(NewDefaultPopupOK("Couldn't ban host",
+					"Enter a valid IP address or subnet", gss.Color(base.CBrightRed), nil))
+				return nil
+			}
+			if err != nil || hours <= 0 || hours > banMaxHours {
+				AddPopup(NewDefaultPopupOK("Couldn't ban host",
+					fmt.Sprintf("Enter a duration from one to %d hours (a year)", banMaxHours),
+					gss.Color(base.CBrightRed), nil))
+				return nil
+			}
+			banHostInput.Delegate.SetValue("")
+			return s.setBan(host, true, uint32(hours*3600))
+		})
+
+	bansPane = NewScreenPane("Active Bans", gss.Color(base.CBrightRed))
+	banAddPane = NewScreenPane(
+		"Ban Host",
+		gss.Color(base.CYellow),
+		banHostInput,
+		banDurationInput,
+		banAddButton,
+	)
+	s.updateBanItems()
+	s.items = append(s.items, bansPane, banAddPane)
+	s.init = true
+	return nil
+}
+
+func (s *Bans) newBanButton(host string) *ScreenButton {
+	return NewScreenButton(host, gss.Color(base.CWhite),
+		func(sb *ScreenButton) tea.Cmd {
+			AddPopup(NewDefaultPopupYesNo(
+				"Lift Ban",
+				fmt.Sprintf("Unban %s?", host),
+				gss.Color(base.CYellow),
+				func(sb *ScreenButton) tea.Cmd {
+					return s.setBan(host, false, 0)
+				}, nil),
+			)
+			return nil
+		})
+}
+
+func (s *Bans) updateBanItems() {
+	focus := bansPane.Focus
+	bansPane.Items = nil
+	if len(s.bans) == 0 {
+		bansPane.Items = append(bansPane.Items,
+			NewScreenLabel("No active bans", gss.Color(base.CGray)),
+		)
+	}
+	buttons := map[string]*ScreenButton{}
+	for _, b := range s.bans {
+		btn, ok := banButtons[b.Host]
+		if !ok {
+			btn = s.newBanButton(b.Host)
+		}
+		buttons[b.Host] = btn
+		bansPane.Items = append(bansPane.Items, btn)
+	}
+	banButtons = buttons
+	WrapPane(bansPane, 0)
+	if focus && !bansPane.IsEnabled() {
+		bansPane.SetFocus(false)
+		UpdateFocus(s, 0)
+	} else {
+		bansPane.SetFocus(focus)
+	}
+}
+
+func sameBans(a, b []rpc_model.GetBansBan) bool {
+	return slices.EqualFunc(a, b, func(x, y rpc_model.GetBansBan) bool {
+		return x.Host == y.Host
+	})
+}
+
+func (s *Bans) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	switch m := msg.(type) {
+	case BanSetMsg:
+		if m.Err != nil {
+			AddPopup(NewDefaultPopupOK("Couldn't update bans",
+				fmt.Sprintf("%s: %v", m.Host, m.Err),
+				gss.Color(base.CBrightRed), nil))
+			return nil
+		}
+		return s.fetchBans
+	case rpc_model.DaemonRPCMsg[rpc_model.DaemonResponseBodyGetBans]:
+		if m.Err != nil {
+			return nil
+		}
+		changed := !sameBans(s.bans, m.Result.Bans)
+		s.bans = m.Result.Bans
+		if changed && s.init {
+			s.updateBanItems()
+		}
+	}
+	return nil
+}
+
+func (s *Bans) View() {
+	if !s.init {
+		return
+	}
+	for _, b := range s.bans {
+		if btn, ok := banButtons[b.Host]; ok {
+			btn.label = fmt.Sprintf("%-20s %s", b.Host,
+				valueStyle.Render(base.FormatSeconds(uint64(b.Seconds))))
+		}
+	}
+}
+
+func (s *Bans) Label() string {
+	return "Bans"
+}
+
+func (s *Bans) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *Bans) Current() *int {
+	return &s.current
+}
+
+func (s *Bans) Next() tea.Msg {
+	return UpdateFocus(s, 1)
+}
+
+func (s *Bans) Prev() tea.Msg {
+	return UpdateFocus(s, -1)
+}
+
+func (s *Bans) Interact(m tea.Model) tea.Cmd {
+	return s.items[s.current].Interact(m)
+}
+
+func (s *Bans) PosVertical() gss.Position {
+	return gss.Position(0.8)
+}
+
+func (s *Bans) PosHorizontal() gss.Position {
+	return gss.Center
+}
+
+func (s *Bans) ItemWidth() int {
+	return 4
+}
+
+func (s *Bans) Vertical() bool {
+	return false
+}
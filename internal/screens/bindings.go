@@ -0,0 +1,91 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+// Widgets showing a config value register here to be refreshed when
+// config.json is changed from outside, e.g. by the front panel or another
+// SSH session.
+
+type configEdit struct {
+	label string
+	popup Popup
+	input *ScreenInputField
+}
+
+var (
+	configBindings = map[string][]func(){}
+	// popups open for editing a key
+	configEdits = map[string]configEdit{}
+)
+
+// Calls rebind now and whenever key changes. Nested keys are joined with
+// dots, like "banlists.dns".
+func bindConfig(key string, rebind func()) {
+	configBindings[key] = append(configBindings[key], rebind)
+	rebind()
+}
+
+// For when the staged values changed wholesale
+func rebindAll() {
+	for _, rebinds := range configBindings {
+		for _, rebind := range rebinds {
+			rebind()
+		}
+	}
+}
+
+// Opens popup for editing key in input
+func editConfig(key, label string, popup Popup, input *ScreenInputField) {
+	configEdits[key] = configEdit{label, popup, input}
+	AddPopup(popup)
+}
+
+// A config value as it is typed. Numbers come as float64 from the JSON,
+// fmt would put the big ones in exponent form.
+func configValue(v any) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// Refreshes the widgets bound to the changed keys. If one of them is being
+// edited the user picks between their edit and the new value.
+func RebindConfig(changes []base.ConfigChange) {
+	for _, c := range changes {
+		for _, rebind := range configBindings[c.Key] {
+			rebind()
+		}
+		edit, ok := configEdits[c.Key]
+		if !ok {
+			continue
+		}
+		if !slices.Contains(Popups, edit.popup) {
+			delete(configEdits, c.Key)
+			continue
+		}
+		newVal := ""
+		if c.New != nil {
+			newVal = configValue(c.New)
+		}
+		if newVal == edit.input.Delegate.Value() {
+			continue
+		}
+		AddPopup(NewDefaultPopupOKCancel("Changed Elsewhere",
+			fmt.Sprintf("%s was just changed to %s outside this session.\nOK loads it into your edit, Cancel keeps what you typed.",
+				edit.label, diffValue(c.Key, c.New)),
+			gss.Color(base.CBrightYellow),
+			func(sb *ScreenButton) tea.Cmd {
+				edit.input.Delegate.SetValue(newVal)
+				return nil
+			}, nil))
+	}
+}
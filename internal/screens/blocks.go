@@ -0,0 +1,244 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/backend/daemonrpc"
+	"github.com/moneronodo/sshui/internal/base"
+	rpc_model "github.com/moneronodo/sshui/internal/model/daemonrpc"
+)
+
+var blocks *Blocks = &Blocks{}
+
+const BlockListSize = 10
+
+var (
+	blocksLabel *ScreenLabel
+	blocksPane  *ScreenPane
+	lookupPane  *ScreenPane
+
+	blockLookupInput  *ScreenInputField
+	blockLookupButton *ScreenButton
+)
+
+var (
+	blockHashPattern = regexp.MustCompile("^[0-9a-fA-F]{64}$")
+	blockHdrStyle    = gss.NewStyle().Bold(true).Foreground(gss.Color(base.CWhite))
+	blockHashStyle   = gss.NewStyle().Foreground(gss.Color(base.CBrightPurple))
+)
+
+type Blocks struct {
+	init    bool
+	daemon  DaemonClient
+	top     rpc_model.BlockHeader
+	headers []rpc_model.BlockHeader
+	items   []ScreenItem
+	current int
+}
+
+func NewBlocks(daemon DaemonClient) *Blocks {
+	blocks.daemon = daemon
+	return blocks
+}
+
+func xmr(atomic uint64) string {
+	return fmt.Sprintf("%.4f", float64(atomic)/1e12)
+}
+
+func shorthandHash(hash string) string {
+	if len(hash) < 64 {
+		return hash
+	}
+	return hash[:8] + "..." + hash[56:]
+}
+
+func (s *Blocks) fetchBlockHeaders(top uint64) tea.Cmd {
+	return func() tea.Msg {
+		var start uint64
+		if top >= BlockListSize {
+			start = top - BlockListSize + 1
+		}
+		return daemonrpc.CallMsg(context.Background(), s.daemon, daemonrpc.GetBlockHeadersRange,
+			rpc_model.DaemonRequestParamsGetBlockHeadersRange{
+				StartHeight: start,
+				EndHeight:   top,
+			})
+	}
+}
+
+func blockDetails(h rpc_model.BlockHeader) string {
+	return fmt.Sprintf(
+		"Height: %d\nTime: %s (%s)\nSize: %s (weight %d)\nTransactions: %d\n"+
+			"Reward: %s XMR\nDifficulty: %d\nDepth: %d\nVersion: %d.%d\nPrevious: %s\nMiner tx: %s",
+		h.Height,
+		base.UnixTime(h.Timestamp),
+		base.UnixTimeRelative(h.Timestamp),
+		base.FormatBytes(h.BlockSize),
+		h.BlockWeight,
+		h.NumTxes,
+		xmr(h.Reward),
+		h.Difficulty,
+		h.Depth,
+		h.MajorVersion,
+		h.MinorVersion,
+		h.PrevHash,
+		h.MinerTxHash,
+	)
+}
+
+// The answer to a block lookup
+type BlockLookupMsg struct {
+	Query  string
+	Header rpc_model.BlockHeader
+	Err    error
+}
+
+func (s *Blocks) lookupBlock(query string) tea.Cmd {
+	query = strings.TrimSpace(query)
+	height, perr := strconv.ParseUint(query, 10, 64)
+	if perr != nil && !blockHashPattern.MatchString(query) {
+		AddPopup(NewDefaultPopupOK("Invalid query",
+			"Enter a block height or a 64 character block hash", gss.Color(base.CBrightRed), nil))
+		return nil
+	}
+	daemon := s.daemon
+	return func() tea.Msg {
+		var (
+			r   *rpc_model.DaemonResponseBodyGetBlockHeader
+			err error
+			ctx = context.Background()
+		)
+		if perr == nil {
+			r, err = daemonrpc.Call(ctx, daemon, daemonrpc.GetBlockHeaderByHeight,
+				rpc_model.DaemonRequestParamsGetBlockHeaderByHeight{Height: height})
+		} else {
+			r, err = daemonrpc.Call(ctx, daemon, daemonrpc.GetBlockHeaderByHash,
+				rpc_model.DaemonRequestParamsGetBlockHeaderByHash{Hash: strings.ToLower(query)})
+		}
+		msg := BlockLookupMsg{Query: query, Err: err}
+		if err == nil {
+			msg.Header = r.BlockHeader
+		}
+		return msg
+	}
+}
+
+func (s *Blocks) Init() tea.Msg {
+	blocksLabel = NewScreenLabel("", gss.Color(base.CGray))
+	blocksPane = NewScreenPane("Recent Blocks", gss.Color(base.CBrightPurple), blocksLabel)
+
+	blockLookupInput = NewScreenInputField("", "Block height or hash", gss.Color(base.CWhite))
+	blockLookupInput.Delegate.Width = 64
+	blockLookupButton = NewScreenButton("Look Up", gss.Color(base.CBrightGreen),
+		func(sb *ScreenButton) tea.Cmd {
+			return s.lookupBlock(blockLookupInput.Delegate.Value())
+		})
+	lookupPane = NewScreenPane(
+		"Block Lookup",
+		gss.Color(base.CYellow),
+		blockLookupInput,
+		blockLookupButton,
+	)
+	s.items = append(s.items, blocksPane, lookupPane)
+	s.init = true
+	return nil
+}
+
+func (s *Blocks) Label() string {
+	return "Blocks"
+}
+
+const blockRowFmt = "%-9s %-19s %-20s %-10s %-4s %-10s %s\n"
+
+func (s *Blocks) View() {
+	if !s.init {
+		return
+	}
+	var sb strings.Builder
+	if s.top.Hash != "" {
+		sb.WriteString(fmt.Sprintf("Top block %d\n%s\n\n", s.top.Height, blockHashStyle.Render(s.top.Hash)))
+	}
+	sb.WriteString(blockHdrStyle.Render(strings.TrimSuffix(fmt.Sprintf(blockRowFmt,
+		"Height", "Hash", "Time", "Size", "Txs", "Reward", "Difficulty"), "\n")))
+	sb.WriteString("\n")
+	for _, h := range s.headers {
+		sb.WriteString(fmt.Sprintf(blockRowFmt,
+			strconv.FormatUint(h.Height, 10),
+			shorthandHash(h.Hash),
+			base.UnixTimeRelative(h.Timestamp),
+			base.FormatBytes(h.BlockSize),
+			strconv.Itoa(int(h.NumTxes)),
+			xmr(h.Reward),
+			strconv.FormatUint(h.Difficulty, 10),
+		))
+	}
+	blocksLabel.label = sb.String()
+}
+
+func (s *Blocks) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	switch m := msg.(type) {
+	case rpc_model.DaemonRPCMsg[rpc_model.DaemonResponseBodyGetLastBlockHeader]:
+		if m.Err != nil || m.Result.BlockHeader.Hash == s.top.Hash {
+			return nil
+		}
+		s.top = m.Result.BlockHeader
+		return s.fetchBlockHeaders(s.top.Height)
+	case rpc_model.DaemonRPCMsg[rpc_model.DaemonResponseBodyGetBlockHeadersRange]:
+		if m.Err == nil {
+			s.headers = m.Result.Headers
+			slices.Reverse(s.headers)
+		}
+	case BlockLookupMsg:
+		if m.Err != nil {
+			AddPopup(NewDefaultPopupOK("Block not found",
+				fmt.Sprintf("No block found for %s: %v", m.Query, m.Err), gss.Color(base.CBrightRed), nil))
+			return nil
+		}
+		AddPopup(NewDefaultPopupOK(m.Header.Hash, blockDetails(m.Header),
+			gss.Color(base.CGray), nil))
+	}
+	return nil
+}
+
+func (s *Blocks) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *Blocks) Current() *int {
+	return &s.current
+}
+
+func (s *Blocks) Next() tea.Msg {
+	return UpdateFocus(s, 1)
+}
+
+func (s *Blocks) Prev() tea.Msg {
+	return UpdateFocus(s, -1)
+}
+
+func (s *Blocks) Interact(m tea.Model) tea.Cmd {
+	return s.items[s.current].Interact(m)
+}
+
+func (s *Blocks) PosVertical() gss.Position {
+	return gss.Position(0.2)
+}
+
+func (s *Blocks) PosHorizontal() gss.Position {
+	return gss.Center
+}
+
+func (s *Blocks) ItemWidth() int {
+	return 2
+}
+
+func (s *Blocks) Vertical() bool {
+	return true
+}
@@ -0,0 +1,34 @@
+package screens
+
+import (
+	"fmt"
+	"time"
+
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/model/dbus"
+)
+
+var busStatusStyle = gss.NewStyle().Bold(true).Foreground(gss.Color(base.CBrightRed))
+
+var (
+	busOffline      bool
+	busOfflineSince time.Time
+)
+
+func UpdateBusStatus(msg dbus.BusStatusMsg) {
+	if !msg.Online && !busOffline {
+		busOfflineSince = time.Now()
+	}
+	busOffline = !msg.Online
+}
+
+// Status bar text while the embedded interface is unreachable, empty
+// otherwise
+func BusStatus() string {
+	if !busOffline {
+		return ""
+	}
+	return busStatusStyle.Render(fmt.Sprintf("embedded interface offline since %s, reconnecting",
+		busOfflineSince.Format("15:04")))
+}
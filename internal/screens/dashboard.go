@@ -1,14 +1,14 @@
 package screens
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	gss "github.com/charmbracelet/lipgloss"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/moneronodo/sshui/internal/backend/daemonrpc"
 	"github.com/moneronodo/sshui/internal/base"
 	rpc_model "github.com/moneronodo/sshui/internal/model/daemonrpc"
@@ -29,36 +29,18 @@ var (
 	servicePane  *ScreenPane
 )
 
-type hardwareStatus struct {
-	cpuUsg    float32
-	cpuGhz    float32
-	temp      float32
-	ram       float32
-	ramTotal  float32
-	ssd       float32
-	ssdTotal  float32
-	emmc      float32
-	emmcTotal float32
-	uptime    string
-}
-
-type serviceStatus struct {
-	monerod   string
-	tor       string
-	i2pd      string
-	moneroLws string
-	sshd      string
-	moneropay string
-}
-
 type Dashboard struct {
-	init       bool
-	getInfo    rpc_model.DaemonResponseBodyGetInfo
-	getVersion rpc_model.DaemonResponseBodyGetVersion
-	hardware   hardwareStatus
-	service    serviceStatus
-	items      []ScreenItem
-	current    int
+	init        bool
+	getInfo     rpc_model.DaemonResponseBodyGetInfo
+	getVersion  rpc_model.DaemonResponseBodyGetVersion
+	rpcErr      error
+	rpcErrSince time.Time
+	hardware    dbus_model.HardwareStatus
+	service     dbus_model.ServiceStatus
+	hardwareErr string
+	serviceErr  string
+	items       []ScreenItem
+	current     int
 }
 
 func NewDashboard() *Dashboard {
@@ -112,6 +94,25 @@ func (s *Dashboard) getSyncStatus() string {
 	}
 }
 
+var rpcErrStyle = gss.NewStyle().Bold(true).Foreground(gss.Color(base.CBrightRed))
+
+func (s *Dashboard) rpcErrorStatus() string {
+	var (
+		refused *rpc_model.DaemonConnectionRefusedErr
+		timeout *rpc_model.DaemonTimeoutErr
+		status  *rpc_model.DaemonStatusErr
+	)
+	since := s.rpcErrSince.Format("15:04")
+	switch {
+	case errors.As(s.rpcErr, &refused), errors.As(s.rpcErr, &timeout):
+		return fmt.Sprintf("monerod unreachable since %s", since)
+	case errors.As(s.rpcErr, &status):
+		return fmt.Sprintf("monerod %s since %s", strings.ToLower(status.Status), since)
+	default:
+		return fmt.Sprintf("RPC error: %v", s.rpcErr)
+	}
+}
+
 func (s *Dashboard) View() {
 	if !s.init {
 		return
@@ -130,31 +131,37 @@ func (s *Dashboard) View() {
 	} else {
 		update = "Up to date"
 	}
-	nodeText.label = gss.JoinHorizontal(
-		gss.Top,
-		labelInner.Render(s.getSyncStatus()+`
+	if s.rpcErr != nil {
+		nodeText.label = labelInner.Render(
+			rpcErrStyle.Render(s.rpcErrorStatus()) + "\n\nRetrying every 5 seconds",
+		)
+	} else {
+		nodeText.label = gss.JoinHorizontal(
+			gss.Top,
+			labelInner.Render(s.getSyncStatus()+`
 Block height
 Version
 Out peers
 In  peers
 Update
 Network`),
-		labelInner.Render(fmt.Sprintf(
-			`
+			labelInner.Render(fmt.Sprintf(
+				`
 : %d
 : %s
 : %d
 : %d
 : %s
 : %s`,
-			s.getInfo.Height,
-			s.getInfo.Version,
-			s.getInfo.OutgoingConnectionsCount,
-			s.getInfo.IncomingConnectionsCount,
-			update,
-			online,
-		)),
-	)
+				s.getInfo.Height,
+				s.getInfo.Version,
+				s.getInfo.OutgoingConnectionsCount,
+				s.getInfo.IncomingConnectionsCount,
+				update,
+				online,
+			)),
+		)
+	}
 	hardwareText.label = gss.JoinHorizontal(
 		gss.Top,
 		labelInner.Render(`CPU
@@ -170,19 +177,19 @@ Uptime`),
 : %.1f/%.1f TB (%.0f%%)
 : %.1f/%.1f GB (%.0f%%)
 : %s`,
-			s.hardware.cpuGhz,
-			s.hardware.cpuUsg,
-			s.hardware.temp,
-			s.hardware.ram,
-			s.hardware.ramTotal,
-			s.hardware.ram/s.hardware.ramTotal*100,
-			s.hardware.ssd,
-			s.hardware.ssdTotal,
-			s.hardware.ssd/s.hardware.ssdTotal*100,
-			s.hardware.emmc,
-			s.hardware.emmcTotal,
-			s.hardware.emmc/s.hardware.emmcTotal*100,
-			s.hardware.uptime,
+			s.hardware.CPUClock,
+			s.hardware.CPUUsage,
+			s.hardware.Temperature,
+			s.hardware.RAMUsed,
+			s.hardware.RAMTotal,
+			s.hardware.RAMUsed/s.hardware.RAMTotal*100,
+			s.hardware.SSDUsed,
+			s.hardware.SSDTotal,
+			s.hardware.SSDUsed/s.hardware.SSDTotal*100,
+			s.hardware.EMMCUsed,
+			s.hardware.EMMCTotal,
+			s.hardware.EMMCUsed/s.hardware.EMMCTotal*100,
+			s.hardware.Uptime,
 		)))
 	serviceText.label = gss.JoinHorizontal(
 		gss.Top,
@@ -197,11 +204,11 @@ MoneroPay`),
 : %s
 : %s
 : %s`,
-			s.service.monerod,
-			s.service.tor,
-			s.service.i2pd,
-			s.service.moneroLws,
-			s.service.moneropay,
+			s.service.Monerod,
+			s.service.Tor,
+			s.service.I2pd,
+			s.service.MoneroLws,
+			s.service.Moneropay,
 		)))
 }
 
@@ -222,80 +229,76 @@ func (s *Dashboard) Update(msg tea.Msg, m tea.Model) tea.Cmd {
 		case dbus_model.HardwareStatusReadyNotification:
 			updateStatuses(s, sig.Message)
 		}
-	case rpc_model.DaemonRPCMsg:
-		resp := m.Response.(rpc_model.DaemonRPCResponse)
-		if resp.Error != nil {
-			// TODO handle
-		} else {
-			switch resp.Result.(type) {
-			case *rpc_model.DaemonResponseBodyGetInfo:
-				s.getInfo = *resp.Result.(*rpc_model.DaemonResponseBodyGetInfo)
-			case *rpc_model.DaemonResponseBodyGetVersion:
-				s.getVersion = *resp.Result.(*rpc_model.DaemonResponseBodyGetVersion)
+	case rpc_model.DaemonRPCMsg[rpc_model.DaemonResponseBodyGetInfo]:
+		if m.Err != nil {
+			if s.rpcErr == nil {
+				s.rpcErrSince = time.Now()
 			}
+			s.rpcErr = m.Err
+			return nil
+		}
+		s.rpcErr = nil
+		s.getInfo = *m.Result
+	case rpc_model.DaemonRPCMsg[rpc_model.DaemonResponseBodyGetVersion]:
+		if m.Err == nil {
+			s.getVersion = *m.Result
 		}
-		return nil
 	}
 	return nil
 }
 
-func updateServices(s *Dashboard, str string) {
-	spl := strings.Split(str, "\n")
-	c := cases.Title(language.Und)
-	if len(spl) < 6 {
-		spew.Fprint(base.Dump, "updateHardware: split too small: ")
-		spew.Fdump(base.Dump, spl)
-		return
+// Logs a status report's errors once, not on every report
+func logStatusErr(last *string, what string, err error) {
+	msg := ""
+	if err != nil {
+		msg = err.Error()
 	}
-	s.service.monerod = c.String(strings.Split(spl[0], ":")[1])
-	s.service.tor = c.String(strings.Split(spl[1], ":")[1])
-	s.service.i2pd = c.String(strings.Split(spl[2], ":")[1])
-	s.service.moneroLws = c.String(strings.Split(spl[3], ":")[1])
-	s.service.sshd = c.String(strings.Split(spl[4], ":")[1])
-	s.service.moneropay = c.String(strings.Split(spl[5], ":")[1])
-
+	if msg != *last && err != nil {
+		dbusLog.Warn(what+" status incomplete", "err", err)
+	}
+	*last = msg
 }
 
-func convFl(val string) float32 {
-	f, err := strconv.ParseFloat(val, 32)
-	if err != nil {
-		return -1
+// Keeps what parsed of a status report, the rest is logged
+func updateServices(s *Dashboard, str string) {
+	st, err := dbus_model.ParseServiceStatus(str)
+	logStatusErr(&s.serviceErr, "service", err)
+	c := cases.Title(language.Und)
+	for _, state := range []*string{&st.Monerod, &st.Tor, &st.I2pd, &st.MoneroLws, &st.Sshd, &st.Moneropay} {
+		*state = c.String(*state)
 	}
-	return float32(f)
+	s.service = st
 }
 
 func updateStatuses(s *Dashboard, str string) {
-	spl := strings.Split(str, "\n")
-	if len(spl) < 10 {
-		spew.Fprint(base.Dump, "updateServices: split too small: ")
-		spew.Fdump(base.Dump, spl)
-		return
-	}
-	s.hardware.cpuUsg = convFl(spl[0])
-	s.hardware.cpuGhz = convFl(spl[1])
-	s.hardware.ram = convFl(spl[2])
-	s.hardware.ramTotal = convFl(spl[3])
-	s.hardware.temp = convFl(spl[4])
-	s.hardware.ssd = convFl(spl[5])
-	s.hardware.ssdTotal = convFl(spl[6])
-	s.hardware.emmc = convFl(spl[7])
-	s.hardware.emmcTotal = convFl(spl[8])
-	s.hardware.uptime = spl[9]
+	st, err := dbus_model.ParseHardwareStatus(str)
+	logStatusErr(&s.hardwareErr, "hardware", err)
+	s.hardware = st
 }
 
-func _updateRpc(prog *tea.Program) {
-	j := *daemonrpc.DaemonPost("http://127.0.0.1:18081/json_rpc", daemonrpc.DaemonRequestBodyGetInfo())
-	prog.Send(rpc_model.DaemonRPCMsg{
-		Response: j,
-	})
+func _updateRpc(send func(tea.Msg), daemon DaemonClient) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var b daemonrpc.Batch
+	pending := []interface{ Msg() any }{
+		daemonrpc.Queue(&b, daemonrpc.GetInfo, rpc_model.NoParams{}),
+		daemonrpc.Queue(&b, daemonrpc.GetConnections, rpc_model.NoParams{}),
+		daemonrpc.Queue(&b, daemonrpc.GetBans, rpc_model.NoParams{}),
+		daemonrpc.Queue(&b, daemonrpc.GetLastBlockHeader, rpc_model.NoParams{}),
+		daemonrpc.Queue(&b, daemonrpc.GetTransactionPoolStats, rpc_model.NoParams{}),
+	}
+	daemon.Do(ctx, &b)
+	for _, p := range pending {
+		send(p.Msg())
+	}
 }
 
-func UpdateRPC(prog *tea.Program) {
+func UpdateRPC(prog *tea.Program, daemon DaemonClient) {
 	rpcTick := time.NewTicker(5 * time.Second)
 	defer rpcTick.Stop()
-	_updateRpc(prog)
+	_updateRpc(prog.Send, daemon)
 	for range rpcTick.C {
-		_updateRpc(prog)
+		_updateRpc(prog.Send, daemon)
 	}
 }
 
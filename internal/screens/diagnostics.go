@@ -0,0 +1,128 @@
+package screens
+
+import (
+	"fmt"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+var diagnostics *Diagnostics = &Diagnostics{}
+
+var (
+	diagLogLabel    *ScreenLabel
+	diagClearButton *ScreenButton
+	diagPane        *ScreenPane
+)
+
+var (
+	diagWarnStyle  = gss.NewStyle().Foreground(gss.Color(base.CBrightYellow))
+	diagErrorStyle = gss.NewStyle().Foreground(gss.Color(base.CBrightRed))
+)
+
+// The warnings and errors logged since start, newest at the bottom
+type Diagnostics struct {
+	init    bool
+	follow  bool
+	content []string
+	items   []ScreenItem
+	current int
+}
+
+func NewDiagnostics() *Diagnostics {
+	diagnostics.follow = true
+	return diagnostics
+}
+
+func (s *Diagnostics) Init() tea.Msg {
+	diagLogLabel = NewScreenLabel("", gss.Color(base.CGray))
+	diagClearButton = NewScreenButton("Clear", gss.Color(base.CBrightYellow),
+		func(sb *ScreenButton) tea.Cmd {
+			base.ClearRecentLogs()
+			return nil
+		})
+	diagPane = NewScreenPane("Warnings and Errors", gss.Color(base.CBrightAqua),
+		diagLogLabel, diagClearButton)
+	diagPane.ItemStyle = gss.NewStyle()
+	s.items = append(s.items, diagPane)
+	s.init = true
+	return nil
+}
+
+func (s *Diagnostics) Label() string {
+	return "Diagnostics"
+}
+
+func (s *Diagnostics) View() {
+	if !s.init {
+		return
+	}
+	recs := base.RecentLogs()
+	s.content = s.content[:0]
+	for _, r := range recs {
+		style := diagWarnStyle
+		if r.Level >= slog.LevelError {
+			style = diagErrorStyle
+		}
+		s.content = append(s.content, style.Render(printable(r.String())))
+	}
+	if len(s.content) == 0 {
+		s.content = append(s.content, "nothing to report")
+	}
+	diagLogLabel.label = fmt.Sprintf("%d recent, everything is in %s", len(recs), base.LogFile())
+}
+
+func (s *Diagnostics) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	return nil
+}
+
+func (s *Diagnostics) Content() []string {
+	return s.content
+}
+
+func (s *Diagnostics) Follow() bool {
+	return s.follow
+}
+
+func (s *Diagnostics) Scrolled(offset, height int) {
+	// back at the bottom, keep up again
+	s.follow = offset+height >= len(s.content)
+}
+
+func (s *Diagnostics) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *Diagnostics) Current() *int {
+	return &s.current
+}
+
+func (s *Diagnostics) Next() tea.Msg {
+	return UpdateFocus(s, 1)
+}
+
+func (s *Diagnostics) Prev() tea.Msg {
+	return UpdateFocus(s, -1)
+}
+
+func (s *Diagnostics) Interact(m tea.Model) tea.Cmd {
+	return s.items[s.current].Interact(m)
+}
+
+func (s *Diagnostics) PosVertical() gss.Position {
+	return gss.Top
+}
+
+func (s *Diagnostics) PosHorizontal() gss.Position {
+	return gss.Left
+}
+
+func (s *Diagnostics) ItemWidth() int {
+	return 1
+}
+
+func (s *Diagnostics) Vertical() bool {
+	return true
+}
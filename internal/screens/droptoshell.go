@@ -1,13 +1,34 @@
 package screens
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/base"
 )
 
 var dropToShell *DropToShell = &DropToShell{}
 
+var (
+	shellHomeButton   *ScreenButton
+	shellMoneroButton *ScreenButton
+	shellPane         *ScreenPane
+)
+
+// The shell has exited and sshui is back
+type ShellExitMsg struct {
+	Err error
+}
+
 type DropToShell struct {
+	items   []ScreenItem
 	current int
 }
 
@@ -15,7 +36,132 @@ func NewDropToShell() *DropToShell {
 	return dropToShell
 }
 
+// Set in the environment of the shells, sshui started from one of them
+// would be nested in the one it dropped from
+const SubshellEnv = "SSHUI_SUBSHELL"
+
+// Whether path is the sshui binary running now
+func isSelf(path string) bool {
+	self, err := os.Executable()
+	if err != nil {
+		return false
+	}
+	a, errA := os.Stat(path)
+	b, errB := os.Stat(self)
+	return errA == nil && errB == nil && os.SameFile(a, b)
+}
+
+// $SHELL, unless that is sshui itself, as it is when sshui is the login
+// shell
+func userShell() string {
+	sh := os.Getenv("SHELL")
+	if sh == "" || isSelf(sh) {
+		return "/bin/sh"
+	}
+	return sh
+}
+
+// Where login shells are looked up, a var for the tests
+var passwdPath = "/etc/passwd"
+
+// The login shell passwd gives name, /bin/bash when that wouldn't be a
+// shell: none, nologin, false or sshui itself
+func passwdShell(name string) string {
+	const fallback = "/bin/bash"
+	data, err := os.ReadFile(passwdPath)
+	if err != nil {
+		return fallback
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		f := strings.Split(line, ":")
+		if len(f) < 7 || f[0] != name {
+			continue
+		}
+		sh := strings.TrimSpace(f[6])
+		switch {
+		case sh == "", isSelf(sh):
+			return fallback
+		case filepath.Base(sh) == "nologin", filepath.Base(sh) == "false":
+			return fallback
+		}
+		return sh
+	}
+	return fallback
+}
+
+func subshell(cmd *exec.Cmd) *exec.Cmd {
+	cmd.Env = append(os.Environ(), SubshellEnv+"=1")
+	return cmd
+}
+
+// The user's shell in their home directory. Not a login shell, the login
+// profile may well start sshui.
+func homeShell() *exec.Cmd {
+	cmd := subshell(exec.Command(userShell()))
+	if home, err := os.UserHomeDir(); err == nil {
+		cmd.Dir = home
+	}
+	return cmd
+}
+
+// A shell as Opts.ShellUser in the monero data directory, through sudo
+// unless that's who we already are. The shell is the one passwd names,
+// $SHELL is sshui when sshui is the login shell and sudo -s would start
+// it again. sudo also drops SubshellEnv.
+func moneroShell() *exec.Cmd {
+	sh := passwdShell(base.Opts.ShellUser)
+	var cmd *exec.Cmd
+	if u, err := user.Current(); err == nil && u.Username == base.Opts.ShellUser {
+		cmd = subshell(exec.Command(sh))
+	} else {
+		cmd = subshell(exec.Command("sudo", "-u", base.Opts.ShellUser, "-H", sh))
+	}
+	cmd.Dir = base.Opts.MoneroDir
+	return cmd
+}
+
+func checkMoneroShell() error {
+	if _, err := user.Lookup(base.Opts.ShellUser); err != nil {
+		return err
+	}
+	if fi, err := os.Stat(base.Opts.MoneroDir); err != nil {
+		return err
+	} else if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", base.Opts.MoneroDir)
+	}
+	return nil
+}
+
+// Suspends sshui until the shell exits
+func runShell(cmd *exec.Cmd) tea.Cmd {
+	uiLog.Info("dropping to shell", "args", cmd.Args, "dir", cmd.Dir)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return ShellExitMsg{err}
+	})
+}
+
 func (s *DropToShell) Init() tea.Msg {
+	shellHomeButton = NewScreenButton("Shell in Home", gss.Color(base.CBrightGreen),
+		func(sb *ScreenButton) tea.Cmd {
+			return runShell(homeShell())
+		})
+	shellMoneroButton = NewScreenButton("Shell as "+base.Opts.ShellUser+" in "+base.Opts.MoneroDir,
+		gss.Color(base.CBrightYellow),
+		func(sb *ScreenButton) tea.Cmd {
+			// sudo's complaints would be gone with the shell
+			if err := checkMoneroShell(); err != nil {
+				AddPopup(NewDefaultPopupOK("Couldn't start shell", err.Error(),
+					gss.Color(base.CBrightRed), nil))
+				return nil
+			}
+			return runShell(moneroShell())
+		})
+	shellPane = NewScreenPane("Drop to Shell", gss.Color(base.CBrightGreen),
+		NewScreenLabel("Type exit to get back here.", gss.Color(base.CGray)),
+		shellHomeButton,
+		shellMoneroButton,
+	)
+	s.items = append(s.items, shellPane)
 	return nil
 }
 
@@ -27,19 +173,19 @@ func (s *DropToShell) View() {}
 
 func (s *DropToShell) Update(msg tea.Msg, m tea.Model) tea.Cmd {
 	switch msg := msg.(type) {
-	case ScreenActiveChangeMsg:
-		if msg.Active {
-			switch msg.Screen.(type) {
-			case *DropToShell:
-				return tea.Quit
-			}
+	case ShellExitMsg:
+		// a shell exiting with the status of its last command isn't news
+		var ee *exec.ExitError
+		if msg.Err != nil && !errors.As(msg.Err, &ee) {
+			AddPopup(NewDefaultPopupOK("Couldn't start shell", msg.Err.Error(),
+				gss.Color(base.CBrightRed), nil))
 		}
 	}
 	return nil
 }
 
 func (s *DropToShell) Items() []ScreenItem {
-	return []ScreenItem{}
+	return s.items
 }
 
 func (s *DropToShell) Current() *int {
@@ -47,29 +193,29 @@ func (s *DropToShell) Current() *int {
 }
 
 func (s *DropToShell) Next() tea.Msg {
-	return nil
+	return UpdateFocus(s, 1)
 }
 
 func (s *DropToShell) Prev() tea.Msg {
-	return nil
+	return UpdateFocus(s, -1)
 }
 
 func (s *DropToShell) Interact(m tea.Model) tea.Cmd {
-	return tea.Quit
+	return s.items[s.current].Interact(m)
 }
 
 func (s *DropToShell) PosVertical() gss.Position {
-	return 0
+	return gss.Position(0.2)
 }
 
 func (s *DropToShell) PosHorizontal() gss.Position {
-	return 0
+	return gss.Center
 }
 
 func (s *DropToShell) ItemWidth() int {
-	return 0
+	return 1
 }
 
 func (s *DropToShell) Vertical() bool {
-	return false
+	return true
 }
@@ -0,0 +1,55 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+func TestMoneroShell(t *testing.T) {
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	passwd := filepath.Join(t.TempDir(), "passwd")
+	if err := os.WriteFile(passwd, []byte(
+		"root:x:0:0:root:/root:/bin/bash\n"+
+			"monero:x:1001:1001::/home/monero:/usr/bin/zsh\n"+
+			"locked:x:1002:1002::/home/locked:/usr/sbin/nologin\n"+
+			"plain:x:1003:1003::/home/plain:\n"+
+			"nodo:x:1004:1004::/home/nodo:"+self+"\n",
+	), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts, path := base.Opts, passwdPath
+	t.Cleanup(func() { base.Opts, passwdPath = opts, path })
+	passwdPath = passwd
+	base.Opts.MoneroDir = "/var/lib/monero"
+
+	for _, tc := range []struct {
+		user string
+		want []string
+	}{
+		{"monero", []string{"sudo", "-u", "monero", "-H", "/usr/bin/zsh"}},
+		{"locked", []string{"sudo", "-u", "locked", "-H", "/bin/bash"}},
+		{"plain", []string{"sudo", "-u", "plain", "-H", "/bin/bash"}},
+		// sshui as the login shell would start sshui again
+		{"nodo", []string{"sudo", "-u", "nodo", "-H", "/bin/bash"}},
+		{"nobody-here", []string{"sudo", "-u", "nobody-here", "-H", "/bin/bash"}},
+	} {
+		base.Opts.ShellUser = tc.user
+		for _, sh := range []string{"", "/bin/sh", self} {
+			t.Setenv("SHELL", sh)
+			cmd := moneroShell()
+			if !slices.Equal(cmd.Args, tc.want) {
+				t.Errorf("%s with SHELL=%q: %q, want %q", tc.user, sh, cmd.Args, tc.want)
+			}
+			if cmd.Dir != base.Opts.MoneroDir {
+				t.Errorf("%s: runs in %s", tc.user, cmd.Dir)
+			}
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/backend/i_dbus"
+	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/model/dbus"
+)
+
+// What has to be typed before the reset button does anything
+const factoryResetPhrase = "erase my nodo"
+
+// How long without a word from the device before the reset view can be
+// left, in case the signal that ends it never comes
+const factoryResetQuiet = 10 * time.Minute
+
+var (
+	resetPhraseInput   *ScreenInputField
+	resetPasswordInput *ScreenInputField
+	resetButton        *ScreenButton
+	resetPane          *ScreenPane
+)
+
+var (
+	resetTitleStyle = gss.NewStyle().Bold(true).Foreground(gss.Color(base.CBrightRed))
+	resetDoneStyle  = gss.NewStyle().Foreground(gss.Color(base.CBrightGreen))
+	resetWaitStyle  = gss.NewStyle().Foreground(gss.Color(base.CGray))
+	resetBoxStyle   = gss.NewStyle().Border(gss.ThickBorder()).
+			BorderForeground(gss.Color(base.CBrightRed)).Padding(1, 4)
+)
+
+type FactoryResetTickMsg struct{}
+
+// A factory reset from the call, or the device announcing one, until the
+// device goes down with it
+type factoryReset struct {
+	called, requested, started, completed time.Time
+	// the last reset signal
+	heard time.Time
+	// the bus went away, which the reboot at the end does
+	offline bool
+	ticking bool
+}
+
+func newFactoryReset() *factoryReset {
+	return &factoryReset{heard: time.Now()}
+}
+
+// Nothing more to wait for, or no telling whether there is. A request on
+// the device alone may never go anywhere.
+func (r *factoryReset) over() bool {
+	return !r.completed.IsZero() || r.offline || time.Since(r.heard) > factoryResetQuiet ||
+		r.called.IsZero() && r.started.IsZero()
+}
+
+// When the reset was first heard of, here or from the device
+func (r *factoryReset) begin() time.Time {
+	b := r.heard
+	for _, t := range []time.Time{r.called, r.requested, r.started} {
+		if !t.IsZero() && t.Before(b) {
+			b = t
+		}
+	}
+	return b
+}
+
+var reset *factoryReset
+
+// Keeps the elapsed time going, one tick at a time
+func (r *factoryReset) tick() tea.Cmd {
+	if r.ticking {
+		return nil
+	}
+	r.ticking = true
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return FactoryResetTickMsg{}
+	})
+}
+
+// Whether the reset screen takes over the whole UI
+func FactoryResetting() bool {
+	return reset != nil
+}
+
+// Back to the UI, once there's nothing more to wait for
+func DismissFactoryReset() {
+	if reset != nil && reset.over() {
+		reset = nil
+	}
+}
+
+func resetLine(label string, at time.Time, since time.Time) string {
+	if at.IsZero() {
+		return resetWaitStyle.Render("  " + label)
+	}
+	return resetDoneStyle.Render(fmt.Sprintf("✓ %s (%s)", label, elapsed(since, at)))
+}
+
+// The full screen view while resetting
+func FactoryResetView() string {
+	r := reset
+	begin := r.begin()
+	requested := r.called
+	if requested.IsZero() || !r.requested.IsZero() && r.requested.Before(requested) {
+		requested = r.requested
+	}
+	lines := []string{resetTitleStyle.Render("Factory Reset"), ""}
+	// not when it was started on the device without asking
+	if !requested.IsZero() {
+		lines = append(lines, resetLine("Requested", requested, begin))
+	}
+	lines = append(lines,
+		resetLine("Started", r.started, begin),
+		resetLine("Completed", r.completed, begin),
+		"",
+	)
+	switch {
+	case !r.completed.IsZero():
+		lines = append(lines, "The device is rebooting with its factory settings.",
+			"This session will end, press enter if it doesn't.")
+	case r.offline:
+		lines = append(lines, "The device went offline, it is probably rebooting.",
+			"Press enter to go back.")
+	case r.called.IsZero() && r.started.IsZero():
+		lines = append(lines, "The device asked for a factory reset.",
+			"Press enter to go back, this screen returns if it starts.")
+	case r.over():
+		lines = append(lines, fmt.Sprintf("Nothing heard from the device for %v.", factoryResetQuiet),
+			"Press enter to go back.")
+	case r.started.IsZero():
+		lines = append(lines, "Waiting for the reset to start, "+elapsed(begin, time.Time{}),
+			"Do not power off the device.")
+	default:
+		lines = append(lines, "Erasing, running for "+elapsed(r.started, time.Time{}),
+			"Do not power off the device.")
+	}
+	lines = append(lines, resetWaitStyle.Render("ctrl+c quits sshui, the reset goes on"))
+	return resetBoxStyle.Render(strings.Join(lines, "\n"))
+}
+
+// The pane in System that starts a reset
+func newResetPane(bus SystemBus) *ScreenPane {
+	resetPhraseInput = NewScreenInputField("", "type: "+factoryResetPhrase, gss.Color(base.CBrightBlack))
+	resetPhraseInput.Delegate.Width = 24
+	resetPasswordInput = pwdField("Your Password")
+	resetButton = NewScreenButton("Factory Reset", gss.Color(base.CBrightRed),
+		func(sb *ScreenButton) tea.Cmd {
+			if recoveryBlocks("A factory reset") {
+				return nil
+			}
+			password := resetPasswordInput.Delegate.Value()
+			AddPopup(NewDefaultPopupYesNo("Factory Reset",
+				"Everything on the device, the blockchain, wallets and settings included, "+
+					"will be erased. Go ahead?",
+				gss.Color(base.CBrightRed),
+				func(sb *ScreenButton) tea.Cmd {
+					resetPhraseInput.Delegate.SetValue("")
+					resetPasswordInput.Delegate.SetValue("")
+					return i_dbus.CallCmd(bus, i_dbus.FactoryReset, password)
+				}, nil))
+			return nil
+		})
+	resetButton.enabled = false
+	return NewScreenPane("Factory Reset", gss.Color(base.CRed),
+		resetPhraseInput, resetPasswordInput, resetButton)
+}
+
+// Follows the reset through the call's result and the reset signals
+func updateFactoryReset(msg tea.Msg) tea.Cmd {
+	resetButton.enabled = strings.TrimSpace(resetPhraseInput.Delegate.Value()) == factoryResetPhrase &&
+		resetPasswordInput.Delegate.Value() != ""
+	switch msg := msg.(type) {
+	case dbus.DbusCallMsg:
+		if msg.Method != i_dbus.FactoryReset.Name {
+			return nil
+		}
+		if msg.Err != nil {
+			AddPopup(NewDefaultPopupOK("Factory reset failed", msg.Err.Error(),
+				gss.Color(base.CBrightRed), nil))
+			return nil
+		}
+		r := ensureReset()
+		r.called = time.Now()
+		return r.tick()
+	case dbus.DbusSignalMsg:
+		now := time.Now()
+		switch msg.Signal.(type) {
+		case dbus.FactoryResetRequested:
+			ensureReset().requested = now
+		case dbus.FactoryResetStarted:
+			ensureReset().started = now
+		case dbus.FactoryResetCompleted:
+			ensureReset().completed = now
+		default:
+			return nil
+		}
+		reset.heard = now
+		return reset.tick()
+	case dbus.BusStatusMsg:
+		if reset != nil {
+			reset.offline = !msg.Online
+		}
+	case FactoryResetTickMsg:
+		if reset != nil {
+			reset.ticking = false
+			if !reset.over() {
+				return reset.tick()
+			}
+		}
+	}
+	return nil
+}
+
+// The reset under way, or one the device went into on its own
+func ensureReset() *factoryReset {
+	if reset == nil {
+		reset = newFactoryReset()
+	}
+	return reset
+}
@@ -0,0 +1,263 @@
+package screens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/moneronodo/sshui/internal/backend/daemonrpc"
+	"github.com/moneronodo/sshui/internal/backend/i_moneropay"
+	"github.com/moneronodo/sshui/internal/backend/i_systemd"
+	"github.com/moneronodo/sshui/internal/model/lws"
+	"github.com/moneronodo/sshui/internal/model/moneropay"
+)
+
+// Stand-ins for the backends. Each answers from what the test put in and
+// notes the calls made, commands run them on goroutines of their own.
+
+type fakeCall struct {
+	method string
+	args   any
+}
+
+// monerod, through Batch.Serve. Results are marshalled the way monerod
+// would send them, a func(params any) any answers from the params.
+type fakeDaemon struct {
+	mu      sync.Mutex
+	results map[string]any
+	errs    map[string]error
+	calls   []fakeCall
+}
+
+func newFakeDaemon() *fakeDaemon {
+	return &fakeDaemon{results: map[string]any{}, errs: map[string]error{}}
+}
+
+func (d *fakeDaemon) Do(ctx context.Context, b *daemonrpc.Batch) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b.Serve(func(method string, params any) (json.RawMessage, error) {
+		d.calls = append(d.calls, fakeCall{method, params})
+		if err := d.errs[method]; err != nil {
+			return nil, err
+		}
+		r, ok := d.results[method]
+		if !ok {
+			return nil, fmt.Errorf("fake daemon: no result for %s", method)
+		}
+		if fn, ok := r.(func(params any) any); ok {
+			r = fn(params)
+		}
+		return json.Marshal(r)
+	})
+}
+
+func (d *fakeDaemon) called(method string) []any {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var args []any
+	for _, c := range d.calls {
+		if c.method == method {
+			args = append(args, c.args)
+		}
+	}
+	return args
+}
+
+// The Nodo embedded interface
+type fakeBus struct {
+	mu    sync.Mutex
+	errs  map[string]error
+	calls []fakeCall
+}
+
+func newFakeBus() *fakeBus {
+	return &fakeBus{errs: map[string]error{}}
+}
+
+func (b *fakeBus) Call(ctx context.Context, method string, args ...any) ([]any, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, fakeCall{method, args})
+	return nil, b.errs[method]
+}
+
+func (b *fakeBus) called() []fakeCall {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]fakeCall(nil), b.calls...)
+}
+
+// monero-lws-admin, keeping the accounts it is told about
+type fakeLWS struct {
+	accounts lws.LwsListAccounts
+	// for AddAccount
+	err   error
+	calls []fakeCall
+}
+
+func (l *fakeLWS) ListAccounts() (lws.LwsListAccounts, error) {
+	return l.accounts, nil
+}
+
+func (l *fakeLWS) AddAccount(address, viewkey string) error {
+	l.calls = append(l.calls, fakeCall{"add", address})
+	if l.err != nil {
+		return l.err
+	}
+	l.accounts.Active = append(l.accounts.Active, lws.LwsAccount{Address: address})
+	return nil
+}
+
+// Moves the account at address from one list to another
+func (l *fakeLWS) move(address string, from, to *[]lws.LwsAccount, status lws.LwsAccountStatus) error {
+	for i, a := range *from {
+		if a.Address == address {
+			*from = append((*from)[:i], (*from)[i+1:]...)
+			a.Status = status
+			*to = append(*to, a)
+			return nil
+		}
+	}
+	return errors.New("fake lws: no account " + address)
+}
+
+func (l *fakeLWS) DeleteAccount(address string) error {
+	l.calls = append(l.calls, fakeCall{"delete", address})
+	if l.move(address, &l.accounts.Active, &l.accounts.Hidden, lws.LwsAccountHidden) != nil {
+		return l.move(address, &l.accounts.Inactive, &l.accounts.Hidden, lws.LwsAccountHidden)
+	}
+	return nil
+}
+
+func (l *fakeLWS) DeactivateAccount(address string) error {
+	l.calls = append(l.calls, fakeCall{"deactivate", address})
+	return l.move(address, &l.accounts.Active, &l.accounts.Inactive, lws.LwsAccountInactive)
+}
+
+func (l *fakeLWS) ReactivateAccount(address string) error {
+	l.calls = append(l.calls, fakeCall{"reactivate", address})
+	return l.move(address, &l.accounts.Inactive, &l.accounts.Active, lws.LwsAccountActive)
+}
+
+func (l *fakeLWS) Rescan(address string, height int) error {
+	l.calls = append(l.calls, fakeCall{"rescan", address})
+	return nil
+}
+
+// A MoneroPay instance
+type fakeStore struct {
+	health   moneropay.MoneropayHealth
+	txs      []i_moneropay.Transaction
+	received map[string]moneropay.MoneropayReceive
+}
+
+func (s *fakeStore) Health() moneropay.MoneropayHealth {
+	return s.health
+}
+
+func (s *fakeStore) TxList() []i_moneropay.Transaction {
+	return append([]i_moneropay.Transaction(nil), s.txs...)
+}
+
+func (s *fakeStore) Receive(address string) (moneropay.MoneropayReceive, error) {
+	r, ok := s.received[address]
+	if !ok {
+		return r, errors.New("fake moneropay: no receiver " + address)
+	}
+	return r, nil
+}
+
+// systemd, with units that are whatever the test says
+type fakeUnits struct {
+	mu      sync.Mutex
+	status  map[string]i_systemd.UnitStatus
+	journal map[string][]i_systemd.JournalEntry
+	errs    map[string]error
+	calls   []fakeCall
+}
+
+func newFakeUnits() *fakeUnits {
+	return &fakeUnits{
+		status:  map[string]i_systemd.UnitStatus{},
+		journal: map[string][]i_systemd.JournalEntry{},
+		errs:    map[string]error{},
+	}
+}
+
+func (u *fakeUnits) do(method, unit string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.calls = append(u.calls, fakeCall{method, unit})
+	return u.errs[method]
+}
+
+func (u *fakeUnits) Start(ctx context.Context, unit string) error {
+	return u.do("start", unit)
+}
+
+func (u *fakeUnits) Stop(ctx context.Context, unit string) error {
+	return u.do("stop", unit)
+}
+
+func (u *fakeUnits) Restart(ctx context.Context, unit string) error {
+	return u.do("restart", unit)
+}
+
+func (u *fakeUnits) Enable(ctx context.Context, unit string) error {
+	if err := u.do("enable", unit); err != nil {
+		return err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	st := u.status[unit]
+	st.UnitFileState = "enabled"
+	u.status[unit] = st
+	return nil
+}
+
+func (u *fakeUnits) Disable(ctx context.Context, unit string) error {
+	if err := u.do("disable", unit); err != nil {
+		return err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	st := u.status[unit]
+	st.UnitFileState = "disabled"
+	u.status[unit] = st
+	return nil
+}
+
+func (u *fakeUnits) Status(ctx context.Context, unit string) (i_systemd.UnitStatus, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	st, ok := u.status[unit]
+	if !ok {
+		return st, errors.New("fake systemd: no unit " + unit)
+	}
+	st.Unit = unit
+	return st, nil
+}
+
+func (u *fakeUnits) Entries(unit string, lines, priority int) ([]i_systemd.JournalEntry, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var entries []i_systemd.JournalEntry
+	for _, e := range u.journal[unit] {
+		if e.Priority <= priority {
+			entries = append(entries, e)
+		}
+	}
+	return entries[max(0, len(entries)-lines):], nil
+}
+
+func (u *fakeUnits) Journal(unit string, lines int) ([]string, error) {
+	entries, err := u.Entries(unit, lines, i_systemd.PrioDebug)
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.String())
+	}
+	return out, err
+}
@@ -22,11 +22,13 @@ var (
 type FirstBoot struct {
 	init    bool
 	set     bool
+	bus     SystemBus
 	items   []ScreenItem
 	current int
 }
 
-func NewFirstBoot() *FirstBoot {
+func NewFirstBoot(bus SystemBus) *FirstBoot {
+	firstBoot.bus = bus
 	return firstBoot
 }
 
@@ -46,7 +48,7 @@ func (s *FirstBoot) Init() tea.Msg {
 	setPassword = NewScreenButton("Submit", gss.Color(base.CBrightGreen),
 		func(sb *ScreenButton) tea.Cmd {
 			if password.Delegate.Value() == passwordRepeat.Delegate.Value() {
-				i_dbus.Call("setPassword", password.Delegate.Value())
+				return i_dbus.CallCmd(s.bus, i_dbus.SetPassword, password.Delegate.Value())
 			}
 			return nil
 		},
@@ -100,6 +102,14 @@ func (s *FirstBoot) Update(msg tea.Msg, m tea.Model) tea.Cmd {
 		setPassword.enabled = false
 	}
 	switch msg := msg.(type) {
+	case dbus.DbusCallMsg:
+		switch msg.Method {
+		case i_dbus.SetPassword.Name:
+			// success is reported by passwordChangeStatus
+			callPopup(msg, "Setting password", "")
+		case i_dbus.Restart.Name:
+			callPopup(msg, "Reboot", "Your Nodo is rebooting, this session will end shortly.")
+		}
 	case dbus.DbusSignalMsg:
 		switch msg.Signal.(type) {
 		case dbus.PasswordChangeStatus:
@@ -109,8 +119,7 @@ func (s *FirstBoot) Update(msg tea.Msg, m tea.Model) tea.Cmd {
 					"Password changed. Your device will now reboot.",
 					gss.Color(base.CGreen),
 					func(sb *ScreenButton) tea.Cmd {
-						i_dbus.Call("restart")
-						return nil
+						return i_dbus.CallCmd(s.bus, i_dbus.Restart)
 					},
 				),
 			)
@@ -0,0 +1,315 @@
+package screens
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// How long a command gets to answer. Ticks take longer and are left out,
+// tests that need one send its message themselves.
+const cmdWait = 200 * time.Millisecond
+
+// The package directory, the tests run in a temporary one so the paths
+// the screens show are the same everywhere
+var pkgDir string
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	var err error
+	if pkgDir, err = os.Getwd(); err != nil {
+		panic(err)
+	}
+	dir, err := os.MkdirTemp("", "sshui-screens")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	// no telling where the tests run
+	time.Local = time.UTC
+
+	base.Opts.ConfigPath = "config.json"
+	if err := writeConfig(); err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile("firstboot", nil, 0o644); err != nil {
+		panic(err)
+	}
+	base.Opts.LogDir = "logs"
+	if err := base.InitLog(); err != nil {
+		panic(err)
+	}
+	if err := base.LoadConfig(); err != nil {
+		panic(err)
+	}
+	return m.Run()
+}
+
+func writeConfig() error {
+	conf, err := os.ReadFile(filepath.Join(pkgDir, "testdata", "config.json"))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(base.Opts.ConfigPath, conf, 0o644)
+}
+
+// Puts the config back as it was once t is done with it
+func restoreConfig(t *testing.T) {
+	t.Cleanup(func() {
+		base.DiscardChanges()
+		if err := os.RemoveAll("config.history"); err != nil {
+			t.Error(err)
+		}
+		if err := writeConfig(); err != nil {
+			t.Error(err)
+		}
+		if err := base.LoadConfig(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// Drives a screen the way the program does, without a terminal: keys go
+// where main sends them, commands run and what they return comes back.
+type harness struct {
+	t      *testing.T
+	screen Screen
+	styles *base.Styles
+	// what changes from run to run, masked in the golden files
+	masks []mask
+}
+
+type mask struct {
+	re   *regexp.Regexp
+	with string
+	fn   func(string) string
+}
+
+// Initialises s and tabs to it, s has to be fresh
+func open(t *testing.T, s Screen) *harness {
+	t.Helper()
+	Popups = nil
+	h := &harness{t: t, screen: s, styles: base.InitStyles(1, 0, 0, 1)}
+	h.send(s.Init())
+	for _, i := range s.Items() {
+		if p, ok := i.(*ScreenPane); ok {
+			WrapPane(p, 0)
+		}
+	}
+	WrapScreen(s, 0)
+	h.send(ScreenActiveChangeMsg{Active: false, Screen: s})
+	return h
+}
+
+// Enters the screen as enter on its tab does
+func (h *harness) enter() {
+	UpdateFocus(h.screen, 0)
+	h.send(ScreenActiveChangeMsg{Active: true, Screen: h.screen})
+	h.view()
+}
+
+func (h *harness) mask(re, with string) {
+	h.masks = append(h.masks, mask{re: regexp.MustCompile(re), with: with})
+}
+
+// Masks inside a box, with as wide as the match so the border stays put.
+// re takes the padding after the text along.
+func (h *harness) maskCell(re, with string) {
+	h.masks = append(h.masks, mask{re: regexp.MustCompile(re), fn: func(s string) string {
+		return with + strings.Repeat(" ", max(0, len(s)-len(with)))
+	}})
+}
+
+// Passes msg to the screen and its items, then runs the commands they
+// return
+func (h *harness) send(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case nil:
+		return
+	case tea.BatchMsg:
+		for _, c := range msg {
+			h.run(c)
+		}
+		return
+	}
+	var cmds []tea.Cmd
+	if j, ok := msg.(ServiceJobMsg); ok {
+		cmds = append(cmds, j.Continue())
+	}
+	for _, i := range h.screen.Items() {
+		cmds = append(cmds, i.Update(msg, nil))
+	}
+	cmds = append(cmds, h.screen.Update(msg, nil))
+	for _, c := range cmds {
+		h.run(c)
+	}
+}
+
+func (h *harness) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	c := make(chan tea.Msg, 1)
+	go func() {
+		c <- cmd()
+	}()
+	select {
+	case msg := <-c:
+		h.send(msg)
+	case <-time.After(cmdWait):
+	}
+}
+
+func keyMsg(k string) tea.KeyMsg {
+	for t, name := range map[tea.KeyType]string{
+		tea.KeyUp: "up", tea.KeyDown: "down", tea.KeyEnter: "enter", tea.KeyEsc: "esc",
+		tea.KeyTab: "tab", tea.KeyShiftTab: "shift+tab", tea.KeyBackspace: "backspace",
+	} {
+		if k == name {
+			return tea.KeyMsg{Type: t}
+		}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// Presses keys, a name such as "down" or "enter" or text to type
+func (h *harness) press(keys ...string) {
+	for _, k := range keys {
+		h.key(keyMsg(k))
+		// input fields take keys once they were drawn with the focus
+		h.view()
+	}
+}
+
+// As main passes keys on, to the popup on top when there is one
+func (h *harness) key(msg tea.KeyMsg) {
+	if len(Popups) > 0 {
+		p := Popups[0]
+		switch msg.String() {
+		case "up":
+			h.send(p.Prev())
+		case "down":
+			h.send(p.Next())
+		case "esc":
+			ClosePopup(p)
+		case "enter":
+			c := p.Interact(nil)
+			if len(p.Items()) > 0 {
+				if _, ok := p.Items()[p.Current()].(*ScreenButton); ok {
+					ClosePopup(p)
+				}
+			}
+			h.run(c)
+		}
+		return
+	}
+	s := h.screen
+	switch msg.String() {
+	case "down", "tab":
+		h.send(s.Next())
+		return
+	case "up", "shift+tab":
+		h.send(s.Prev())
+		return
+	case "esc":
+		h.send(ScreenActiveChangeMsg{Active: false, Screen: s})
+		return
+	case "enter":
+		if len(s.Items()) == 0 {
+			return
+		}
+		c := s.Interact(nil)
+		if c == nil {
+			c = s.Next
+		}
+		h.send(msg)
+		h.run(c)
+		return
+	}
+	h.send(msg)
+}
+
+// The screen as the content area shows it, with the popup on top below
+// it, without colours and trailing blanks
+func (h *harness) view() string {
+	s := h.screen
+	s.View()
+	var it []string
+	for _, i := range s.Items() {
+		it = append(it, h.styles.ContentItem.BorderForeground(i.GetColor()).Render(i.Render()))
+	}
+	out := gss.JoinHorizontal(gss.Top, it...)
+	if s.Vertical() {
+		out = gss.JoinVertical(gss.Left, it...)
+	}
+	if ss, ok := s.(ScrollScreen); ok {
+		// all of it in view
+		ss.Scrolled(0, len(ss.Content()))
+		out += "\n" + strings.Join(ss.Content(), "\n")
+	}
+	if len(Popups) > 0 {
+		out += "\n\npopup:\n" + Popups[0].Render()
+	}
+	if t := Toasts(); t != "" {
+		out += "\n\ntoasts:\n" + t
+	}
+	if FactoryResetting() {
+		out += "\n\nfactory reset:\n" + FactoryResetView()
+	}
+	out = ansi.Strip(out)
+	for _, m := range h.masks {
+		if m.fn != nil {
+			out = m.re.ReplaceAllStringFunc(out, m.fn)
+		} else {
+			out = m.re.ReplaceAllString(out, m.with)
+		}
+	}
+	lines := strings.Split(out, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Compares the view with testdata/<screen>/<step>.golden, or writes it
+// there with -update
+func (h *harness) golden(step string) {
+	h.t.Helper()
+	dir := strings.ToLower(strings.TrimPrefix(h.t.Name(), "Test"))
+	path := filepath.Join(pkgDir, "testdata", dir, step+".golden")
+	got := h.view()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			h.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			h.t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		h.t.Fatalf("%v, run with -update to create it", err)
+	}
+	if got != string(want) {
+		h.t.Errorf("%s differs from %s:\n%s", step, path, got)
+	}
+}
@@ -0,0 +1,205 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+var history *History = &History{}
+
+const historyListHeight = 10
+
+var (
+	historyList      *ScreenList
+	historyDiffLabel *ScreenLabel
+
+	historyListPane *ScreenPane
+	historyDiffPane *ScreenPane
+)
+
+var (
+	diffOldStyle = gss.NewStyle().Foreground(gss.Color(base.CRed))
+	diffNewStyle = gss.NewStyle().Foreground(gss.Color(base.CGreen))
+)
+
+// Earlier versions of config.json and how they differ from the current one
+type History struct {
+	init    bool
+	snaps   []base.Snapshot
+	diffs   [][]base.ConfigChange
+	items   []ScreenItem
+	current int
+}
+
+func NewHistory() *History {
+	return history
+}
+
+func diffValue(key string, v any) string {
+	switch {
+	case v == nil:
+		return "(unset)"
+	case key == "rpcp":
+		return "********"
+	}
+	return configValue(v)
+}
+
+func formatDiff(changes []base.ConfigChange) string {
+	if len(changes) == 0 {
+		return "Same as the current config"
+	}
+	var sb strings.Builder
+	for _, c := range changes {
+		sb.WriteString(fmt.Sprintf("%s: %s -> %s\n", c.Key,
+			diffOldStyle.Render(diffValue(c.Key, c.Old)),
+			diffNewStyle.Render(diffValue(c.Key, c.New)),
+		))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (s *History) refresh() {
+	snaps, err := base.Snapshots()
+	if err != nil {
+		configLog.Error("listing snapshots failed", "err", err)
+	}
+	cur := *base.GetConfig()
+	s.snaps = nil
+	s.diffs = nil
+	rows := []string{}
+	for _, snap := range snaps {
+		c, err := base.LoadSnapshot(snap)
+		if err != nil {
+			configLog.Warn("skipping snapshot", "path", snap.Path, "err", err)
+			continue
+		}
+		// what rolling back to snap would change
+		d := base.DiffConfig(cur, c)
+		s.snaps = append(s.snaps, snap)
+		s.diffs = append(s.diffs, d)
+		rows = append(rows, fmt.Sprintf("%s  %-16s %2d changes",
+			base.UnixTime(snap.Time.Unix()),
+			base.UnixTimeRelative(snap.Time.Unix()),
+			len(d),
+		))
+	}
+	historyList.SetRows(rows)
+}
+
+func (s *History) rollback(i int) tea.Cmd {
+	if recoveryBlocks("Rolling back the config") {
+		return nil
+	}
+	changes, err := base.RestoreSnapshot(s.snaps[i])
+	if err != nil {
+		AddPopup(NewDefaultPopupOK("Couldn't roll back", err.Error(),
+			gss.Color(base.CBrightRed), nil))
+		return nil
+	}
+	s.refresh()
+	rebindAll()
+	return func() tea.Msg {
+		return base.ConfigSavedMsg{Services: base.AffectedServices(changes)}
+	}
+}
+
+func (s *History) Init() tea.Msg {
+	historyList = NewScreenList(historyListHeight, gss.Color(base.CWhite),
+		func(sl *ScreenList, i int) tea.Cmd {
+			if i >= len(s.snaps) || len(s.diffs[i]) == 0 {
+				return nil
+			}
+			AddPopup(NewDefaultPopupYesNo("Roll Back",
+				fmt.Sprintf("Restore the config from %s and restart the services?\n\n%s",
+					base.UnixTime(s.snaps[i].Time.Unix()), formatDiff(s.diffs[i])),
+				gss.Color(base.CYellow),
+				func(sb *ScreenButton) tea.Cmd {
+					return s.rollback(i)
+				}, nil))
+			return nil
+		})
+	historyDiffLabel = NewScreenLabel("", gss.Color(base.CGray))
+	historyListPane = NewScreenPane("Saved Configs", gss.Color(base.CBrightAqua), historyList)
+	historyDiffPane = NewScreenPane("Rolling Back Changes", gss.Color(base.CYellow), historyDiffLabel)
+	s.refresh()
+	s.items = append(s.items, historyListPane, historyDiffPane)
+	s.init = true
+	return nil
+}
+
+func (s *History) Label() string {
+	return "History"
+}
+
+func (s *History) View() {
+	if !s.init {
+		return
+	}
+	if len(s.snaps) == 0 {
+		historyDiffLabel.label = "No earlier configs saved yet"
+		return
+	}
+	historyDiffLabel.label = formatDiff(s.diffs[historyList.Cursor()])
+}
+
+func (s *History) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	if !s.init {
+		return nil
+	}
+	switch m := msg.(type) {
+	case base.ConfigSavedMsg, base.ConfigChangedMsg:
+		s.refresh()
+	case ScreenActiveChangeMsg:
+		if m.Active && m.Screen == s {
+			s.refresh()
+		}
+	}
+	return nil
+}
+
+func (s *History) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *History) Current() *int {
+	return &s.current
+}
+
+func (s *History) Next() tea.Msg {
+	if historyList.IsFocus() && historyList.Scroll(1) {
+		return FocusChangeMsg{Current: historyListPane}
+	}
+	return UpdateFocus(s, 1)
+}
+
+func (s *History) Prev() tea.Msg {
+	if historyList.IsFocus() && historyList.Scroll(-1) {
+		return FocusChangeMsg{Current: historyListPane}
+	}
+	return UpdateFocus(s, -1)
+}
+
+func (s *History) Interact(m tea.Model) tea.Cmd {
+	return s.items[s.current].Interact(m)
+}
+
+func (s *History) PosVertical() gss.Position {
+	return gss.Position(0.2)
+}
+
+func (s *History) PosHorizontal() gss.Position {
+	return gss.Center
+}
+
+func (s *History) ItemWidth() int {
+	return 2
+}
+
+func (s *History) Vertical() bool {
+	return true
+}
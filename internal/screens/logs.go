@@ -0,0 +1,316 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/moneronodo/sshui/internal/backend/i_systemd"
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+var logs *Logs = &Logs{}
+
+const logsLines = 1000
+
+// The units worth reading the journal of
+var logsUnits = []string{"monerod", "tor", "i2pd", "monero-lws", "moneropay"}
+
+var (
+	logsUnitButton     *ScreenButton
+	logsPriorityButton *ScreenButton
+	logsFollowToggle   *ScreenToggle
+	logsSearchInput    *ScreenInputField
+	logsExportButton   *ScreenButton
+
+	logsJournalPane *ScreenPane
+	logsSearchPane  *ScreenPane
+)
+
+var (
+	logsMatchStyle    = gss.NewStyle().Foreground(gss.Color(base.CBlack)).Background(gss.Color(base.CBrightYellow))
+	logsPriorityStyle = []gss.Style{
+		i_systemd.PrioEmerg:   gss.NewStyle().Foreground(gss.Color(base.CBrightRed)).Bold(true),
+		i_systemd.PrioAlert:   gss.NewStyle().Foreground(gss.Color(base.CBrightRed)).Bold(true),
+		i_systemd.PrioCrit:    gss.NewStyle().Foreground(gss.Color(base.CBrightRed)).Bold(true),
+		i_systemd.PrioErr:     gss.NewStyle().Foreground(gss.Color(base.CBrightRed)),
+		i_systemd.PrioWarning: gss.NewStyle().Foreground(gss.Color(base.CBrightYellow)),
+		i_systemd.PrioNotice:  gss.NewStyle().Foreground(gss.Color(base.CWhite)),
+		i_systemd.PrioInfo:    gss.NewStyle().Foreground(gss.Color(base.CGray)),
+		i_systemd.PrioDebug:   gss.NewStyle().Foreground(gss.Color(base.CBrightBlack)),
+	}
+)
+
+type LogsTickMsg struct{}
+
+type JournalMsg struct {
+	Unit     string
+	Priority int
+	Entries  []i_systemd.JournalEntry
+	Err      error
+}
+
+type Logs struct {
+	init     bool
+	active   bool
+	manager  ServiceManager
+	unit     int
+	priority int
+	fetched  bool
+	fetching bool
+	entries  []i_systemd.JournalEntry
+	err      error
+	// the entries shown and rendered, after searching
+	shown   []i_systemd.JournalEntry
+	content []string
+	offset  int
+	height  int
+	items   []ScreenItem
+	current int
+}
+
+func NewLogs(manager ServiceManager) *Logs {
+	logs.manager = manager
+	logs.priority = i_systemd.PrioInfo
+	return logs
+}
+
+// Re-reads the journal while the screen is in use and following
+func UpdateLogs(prog *tea.Program) {
+	tick := time.NewTicker(2 * time.Second)
+	defer tick.Stop()
+	for range tick.C {
+		prog.Send(LogsTickMsg{})
+	}
+}
+
+// Reads the journal, unless a read is still out. journalctl can take a
+// while, ticks mustn't pile reads up behind it.
+func (s *Logs) fetch() tea.Cmd {
+	if s.fetching {
+		return nil
+	}
+	s.fetching = true
+	unit, prio := logsUnits[s.unit], s.priority
+	return func() tea.Msg {
+		entries, err := s.manager.Entries(unit, logsLines, prio)
+		return JournalMsg{unit, prio, entries, err}
+	}
+}
+
+func (s *Logs) setLabels() {
+	logsUnitButton.label = "Unit: " + logsUnits[s.unit]
+	logsPriorityButton.label = "Priority: " + i_systemd.PriorityNames[s.priority] + " and up"
+}
+
+func (s *Logs) export() tea.Cmd {
+	end := min(s.offset+s.height, len(s.shown))
+	if s.offset >= end {
+		AddPopup(NewDefaultPopupOK("Nothing to Export", "There are no lines in view.",
+			gss.Color(base.CGray), nil))
+		return nil
+	}
+	var sb strings.Builder
+	for _, e := range s.shown[s.offset:end] {
+		sb.WriteString(e.String() + "\n")
+	}
+	path := base.LogPath(fmt.Sprintf("journal-%s-%s.log",
+		logsUnits[s.unit], time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		AddPopup(NewDefaultPopupOK("Couldn't export", err.Error(), gss.Color(base.CBrightRed), nil))
+		return nil
+	}
+	AddPopup(NewDefaultPopupOK("Exported",
+		fmt.Sprintf("Wrote %d lines to %s", end-s.offset, path), gss.Color(base.CBrightGreen), nil))
+	return nil
+}
+
+func (s *Logs) Init() tea.Msg {
+	logsUnitButton = NewScreenButton("", gss.Color(base.CBrightAqua),
+		func(sb *ScreenButton) tea.Cmd {
+			s.unit = (s.unit + 1) % len(logsUnits)
+			s.setLabels()
+			// a read still out is for the old unit and will be dropped
+			s.fetching = false
+			return s.fetch()
+		})
+	logsPriorityButton = NewScreenButton("", gss.Color(base.CBrightAqua),
+		func(sb *ScreenButton) tea.Cmd {
+			// from err down to debug, then around
+			s.priority++
+			if s.priority > i_systemd.PrioDebug {
+				s.priority = i_systemd.PrioErr
+			}
+			s.setLabels()
+			s.fetching = false
+			return s.fetch()
+		})
+	logsFollowToggle = NewScreenToggle("Follow", gss.Color(base.CBrightAqua),
+		func(st *ScreenToggle, toggled bool) tea.Cmd {
+			if toggled {
+				return s.fetch()
+			}
+			return nil
+		})
+	logsFollowToggle.toggled = true
+	logsSearchInput = NewScreenInputField("", "search", gss.Color(base.CBrightAqua))
+	logsSearchInput.Delegate.Width = 30
+	logsExportButton = NewScreenButton("Export Visible Lines", gss.Color(base.CBrightAqua),
+		func(sb *ScreenButton) tea.Cmd {
+			return s.export()
+		})
+	s.setLabels()
+
+	logsJournalPane = NewScreenPane("Journal", gss.Color(base.CAqua),
+		logsUnitButton, logsPriorityButton, logsFollowToggle)
+	logsSearchPane = NewScreenPane("Search", gss.Color(base.CAqua),
+		logsSearchInput, logsExportButton)
+	// keep the controls short to leave room for the lines
+	logsJournalPane.ItemStyle = gss.NewStyle()
+	logsSearchPane.ItemStyle = gss.NewStyle()
+	s.items = append(s.items, logsJournalPane, logsSearchPane)
+	s.init = true
+	return nil
+}
+
+func (s *Logs) Label() string {
+	return "Logs"
+}
+
+// Drops escape sequences and other control characters programs log
+func printable(line string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' && r != '\t' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, ansi.Strip(line))
+}
+
+// Marks every case insensitive match of q in line, over the priority style
+func highlight(line, q string, style gss.Style) string {
+	if q == "" {
+		return style.Render(line)
+	}
+	lower := strings.ToLower(line)
+	if len(lower) != len(line) {
+		lower = line
+	}
+	var sb strings.Builder
+	for {
+		i := strings.Index(lower, q)
+		if i < 0 {
+			break
+		}
+		sb.WriteString(style.Render(line[:i]))
+		sb.WriteString(logsMatchStyle.Render(line[i : i+len(q)]))
+		line, lower = line[i+len(q):], lower[i+len(q):]
+	}
+	sb.WriteString(style.Render(line))
+	return sb.String()
+}
+
+func (s *Logs) View() {
+	if !s.init {
+		return
+	}
+	q := strings.ToLower(logsSearchInput.Delegate.Value())
+	s.shown = s.shown[:0]
+	s.content = s.content[:0]
+	for _, e := range s.entries {
+		// search what is shown, escape bytes could match or split a match
+		line := printable(e.String())
+		if q != "" && !strings.Contains(strings.ToLower(line), q) {
+			continue
+		}
+		style := logsPriorityStyle[min(max(e.Priority, 0), i_systemd.PrioDebug)]
+		s.shown = append(s.shown, e)
+		s.content = append(s.content, highlight(line, q, style))
+	}
+	switch {
+	case s.err != nil:
+		s.content = []string{logsPriorityStyle[i_systemd.PrioErr].Render(s.err.Error())}
+	case len(s.content) == 0 && q != "":
+		s.content = []string{"no lines match"}
+	}
+	logsSearchPane.Title = fmt.Sprintf("Search (%d of %d lines)", len(s.shown), len(s.entries))
+}
+
+func (s *Logs) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case ScreenActiveChangeMsg:
+		s.active = msg.Active && msg.Screen == s
+		if s.active {
+			return s.fetch()
+		}
+	case LogsTickMsg:
+		// the first read shouldn't wait for the screen to be used
+		if s.active && s.Follow() || !s.fetched {
+			return s.fetch()
+		}
+	case JournalMsg:
+		// the read for the current unit and priority is still out
+		if msg.Unit != logsUnits[s.unit] || msg.Priority != s.priority {
+			return nil
+		}
+		s.entries, s.err = msg.Entries, msg.Err
+		s.fetched, s.fetching = true, false
+	}
+	return nil
+}
+
+func (s *Logs) Content() []string {
+	return s.content
+}
+
+func (s *Logs) Follow() bool {
+	return logsFollowToggle.toggled
+}
+
+func (s *Logs) Scrolled(offset, height int) {
+	s.offset, s.height = offset, height
+	// scrolling back stops following
+	if offset+height < len(s.content) {
+		logsFollowToggle.toggled = false
+	}
+}
+
+func (s *Logs) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *Logs) Current() *int {
+	return &s.current
+}
+
+func (s *Logs) Next() tea.Msg {
+	return UpdateFocus(s, 1)
+}
+
+func (s *Logs) Prev() tea.Msg {
+	return UpdateFocus(s, -1)
+}
+
+func (s *Logs) Interact(m tea.Model) tea.Cmd {
+	return s.items[s.current].Interact(m)
+}
+
+func (s *Logs) PosVertical() gss.Position {
+	return gss.Top
+}
+
+func (s *Logs) PosHorizontal() gss.Position {
+	return gss.Left
+}
+
+func (s *Logs) ItemWidth() int {
+	return 2
+}
+
+func (s *Logs) Vertical() bool {
+	return false
+}
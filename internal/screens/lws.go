@@ -9,18 +9,12 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	gss "github.com/charmbracelet/lipgloss"
-	"github.com/davecgh/go-spew/spew"
-	i_lws "github.com/moneronodo/sshui/internal/backend/lws"
 	"github.com/moneronodo/sshui/internal/base"
 	"github.com/moneronodo/sshui/internal/model/lws"
 )
 
 var lightWallet *LightWallet = &LightWallet{}
 
-const (
-	lwsPort int = 18089
-)
-
 var (
 	lwsClearnetAddr *ScreenLabel
 	lwsI2pAddr      *ScreenLabel
@@ -36,27 +30,29 @@ var (
 
 type LightWallet struct {
 	init    bool
+	lws     LWSAdmin
 	items   []ScreenItem
 	current int
 }
 
-func NewLightWallet() *LightWallet {
+func NewLightWallet(admin LWSAdmin) *LightWallet {
+	lightWallet.lws = admin
 	return lightWallet
 }
 
 var inputAcct, inputKey *ScreenInputField
 
 func (s *LightWallet) Init() tea.Msg {
-	lwsClearnetAddr = NewScreenLabel(fmt.Sprintf(" http://%s:%d", clearnetAddr, lwsPort), gss.Color(base.CBlue))
-	lwsOnionAddr = NewScreenLabel(fmt.Sprintf(" http://%s:%d", onionAddr, lwsPort), gss.Color(base.CBlue))
-	lwsI2pAddr = NewScreenLabel(fmt.Sprintf(" http://%s:%d", i2pAddr, lwsPort), gss.Color(base.CBlue))
+	lwsClearnetAddr = NewScreenLabel(fmt.Sprintf(" http://%s:%d", clearnetAddr, base.Opts.LwsPort), gss.Color(base.CBlue))
+	lwsOnionAddr = NewScreenLabel(fmt.Sprintf(" http://%s:%d", onionAddr, base.Opts.LwsPort), gss.Color(base.CBlue))
+	lwsI2pAddr = NewScreenLabel(fmt.Sprintf(" http://%s:%d", i2pAddr, base.Opts.LwsPort), gss.Color(base.CBlue))
 
 	inputAcct = NewScreenInputField("", "Primary address", gss.Color(base.CWhite))
 	inputKey = NewScreenInputField("", "Private view key", gss.Color(base.CWhite))
 
 	lwsAddWalletButton = NewScreenButton("Add Wallet", gss.Color(base.CBrightGreen),
 		func(sb *ScreenButton) tea.Cmd {
-			e := i_lws.AddAccount(inputAcct.Delegate.Value(), inputKey.Delegate.Value())
+			e := s.lws.AddAccount(inputAcct.Delegate.Value(), inputKey.Delegate.Value())
 			if e != nil {
 				AddPopup(
 					NewDefaultPopupOK("Couldn't add wallet", e.Error(), gss.Color(base.CBrightRed), nil),
@@ -88,26 +84,26 @@ func (s *LightWallet) Init() tea.Msg {
 		gss.Color(base.CGreen),
 	)
 
-	UpdateAccounts()
+	s.UpdateAccounts()
 	s.items = append(s.items, lwsInfoPane, lwsAccountsPane)
 	s.init = true
 	return nil
 }
 
-func newLwsAccountButton(l lws.LwsAccount) *ScreenButton {
+func (s *LightWallet) newLwsAccountButton(l lws.LwsAccount) *ScreenButton {
 	var b *ScreenButton
 	if l.Status == lws.LwsAccountActive {
 		b = NewScreenButton("Deactivate", gss.Color(base.CBlue),
 			func(sb *ScreenButton) tea.Cmd {
-				i_lws.DeactivateAccount(l.Address)
-				UpdateAccounts()
+				s.lws.DeactivateAccount(l.Address)
+				s.UpdateAccounts()
 				return nil
 			})
 	} else {
 		b = NewScreenButton("Activate", gss.Color(base.CBlue),
 			func(sb *ScreenButton) tea.Cmd {
-				i_lws.ReactivateAccount(l.Address)
-				UpdateAccounts()
+				s.lws.ReactivateAccount(l.Address)
+				s.UpdateAccounts()
 				return nil
 			})
 	}
@@ -136,8 +132,8 @@ func newLwsAccountButton(l lws.LwsAccount) *ScreenButton {
 								),
 								gss.Color(base.CRed),
 								func(sb *ScreenButton) tea.Cmd {
-									i_lws.DeleteAccount(l.Address)
-									UpdateAccounts()
+									s.lws.DeleteAccount(l.Address)
+									s.UpdateAccounts()
 									return nil
 								}, nil),
 						)
@@ -148,8 +144,8 @@ func newLwsAccountButton(l lws.LwsAccount) *ScreenButton {
 						AddPopup(
 							NewDefaultPopupYesNo("Rescan", "This may take a while, are you sure?", gss.Color(base.CRed),
 								func(sb *ScreenButton) tea.Cmd {
-									i_lws.Rescan(l.Address, int(l.ScanHeight))
-									UpdateAccounts()
+									s.lws.Rescan(l.Address, int(l.ScanHeight))
+									s.UpdateAccounts()
 									return nil
 								}, nil),
 						)
@@ -182,11 +178,11 @@ func shorthandAddress(addr string, groups int, size int) string {
 	return sb.String()
 }
 
-func UpdateAccounts() {
+func (s *LightWallet) UpdateAccounts() {
 	var err error
-	lwsAccounts, err = i_lws.ListAccounts()
+	lwsAccounts, err = s.lws.ListAccounts()
 	if err != nil {
-		spew.Fdump(base.Dump, lwsAccounts, err)
+		lwsLog.Error("listing accounts failed", "err", err)
 		return
 	}
 	lwsAccountsPane.Items = nil
@@ -194,13 +190,13 @@ func UpdateAccounts() {
 		NewScreenLabel("Active", gss.Color(base.CBrightGreen)),
 	)
 	for _, a := range lwsAccounts.Active {
-		lwsAccountsPane.Items = append(lwsAccountsPane.Items, newLwsAccountButton(a))
+		lwsAccountsPane.Items = append(lwsAccountsPane.Items, s.newLwsAccountButton(a))
 	}
 	lwsAccountsPane.Items = append(lwsAccountsPane.Items,
 		NewScreenLabel("Inactive", gss.Color(base.CBrightRed)),
 	)
 	for _, a := range lwsAccounts.Inactive {
-		lwsAccountsPane.Items = append(lwsAccountsPane.Items, newLwsAccountButton(a))
+		lwsAccountsPane.Items = append(lwsAccountsPane.Items, s.newLwsAccountButton(a))
 	}
 }
 
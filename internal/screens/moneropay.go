@@ -1,15 +1,12 @@
 package screens
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	gss "github.com/charmbracelet/lipgloss"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/moneronodo/sshui/internal/backend/i_moneropay"
 	"github.com/moneronodo/sshui/internal/base"
 	"github.com/moneronodo/sshui/internal/model/moneropay"
@@ -17,10 +14,6 @@ import (
 
 var mpay *Moneropay = &Moneropay{}
 
-const (
-	mpayUrl = "http://127.0.0.1:5000"
-)
-
 var (
 	moneropayPane    *ScreenPane
 	transactionsPane *ScreenPane
@@ -45,73 +38,72 @@ func NewMoneropay() *Moneropay {
 	return mpay
 }
 
-func GetTxDetails(address string) (i_moneropay.Transaction, error) {
+func GetTxDetails(store MoneropayStore, address string) (i_moneropay.Transaction, error) {
 	tx := i_moneropay.Transaction{}
-	c := &http.Client{Timeout: 5 * time.Second}
-	resp, err := c.Get(fmt.Sprintf("%s/receive/%s", mpayUrl, address))
+	j, err := store.Receive(address)
 	if err != nil {
 		return i_moneropay.Transaction{}, err
 	}
-	defer resp.Body.Close()
-	dec := json.NewDecoder(resp.Body)
-	j := moneropay.MoneropayReceive{}
-	if err := dec.Decode(&j); err != nil {
-		spew.Fprintf(base.Dump, "Decode: %v\n", err)
-		return i_moneropay.Transaction{}, err
-	}
 	tx.Covered = j.Amount.Covered
 	tx.Complete = j.Complete
 	tx.TxIds = j.Transactions
 	return tx, nil
 }
 
-func _updateMpay(prog *tea.Program) {
-	hlt := *i_moneropay.GetHealth(mpayUrl + "/health")
-	prog.Send(&moneropay.MpayHealthMsg{
+func _updateMpay(send func(tea.Msg), store MoneropayStore) {
+	hlt := store.Health()
+	send(&moneropay.MpayHealthMsg{
 		Health: hlt,
 	})
-	txs := i_moneropay.GetTxList()
-	prog.Send(&i_moneropay.MpayTxListMsg{
+	txs := store.TxList()
+	send(&i_moneropay.MpayTxListMsg{
 		Transactions: txs,
 	})
 	for i := range txs {
 		if i >= len(transactions) || transactions[i].Covered.Total == 0 {
-			t, err := GetTxDetails(txs[i].Subaddress)
+			t, err := GetTxDetails(store, txs[i].Subaddress)
 			if err == nil {
 				txs[i].Covered = t.Covered
 				txs[i].Queried = true
-				prog.Send(&i_moneropay.MpayTxUpdateMsg{
+				send(&i_moneropay.MpayTxUpdateMsg{
 					Index:       i,
 					Transaction: txs[i],
 				})
 			} else {
-				spew.Fdump(base.Dump, err)
+				mpayLog.Warn("transaction details failed", "subaddress", txs[i].Subaddress, "err", err)
 			}
 		}
 	}
 }
 
-func UpdateMpay(prog *tea.Program) {
+func UpdateMpay(prog *tea.Program, store MoneropayStore) {
 	rpcTick := time.NewTicker(5 * time.Second)
 	defer rpcTick.Stop()
-	_updateMpay(prog)
+	_updateMpay(prog.Send, store)
 	for range rpcTick.C {
-		_updateMpay(prog)
+		_updateMpay(prog.Send, store)
 	}
 }
 
 func (s *Moneropay) Init() tea.Msg {
-	addr, _ := base.GetVal("moneropay", "deposit_address").(string)
-	addrLabel = NewScreenLabel(addr, gss.Color(base.CGray))
+	addrLabel = NewScreenLabel("", gss.Color(base.CGray))
+	bindConfig("moneropay.deposit_address", func() {
+		addrLabel.label, _ = base.GetVal("moneropay", "deposit_address").(string)
+	})
 	statusLabel = NewScreenLabel("status pending...", gss.Color(base.CBrightYellow))
 	moneropayPane = NewScreenPane("MoneroPay", gss.Color(base.CYellow))
 	clearAddrButton = NewScreenButton("Clear Address (disable MoneroPay)", gss.Color(base.CBrightYellow),
 		func(sb *ScreenButton) tea.Cmd {
 			AddPopup(NewDefaultPopupYesNo("Restart", "Are you sure?", gss.Color(base.CBrightRed),
 				func(sb *ScreenButton) tea.Cmd {
-					base.SetMpayConfig("deposit_address", "")
-					base.SetMpayConfig("enabled", base.Bool(false))
-					return nil
+					if err := base.SetMpayConfig("enabled", base.Bool(false)); err != nil {
+						return configResult(err)
+					}
+					err := base.SetMpayConfig("deposit_address", "")
+					if err == nil {
+						addrLabel.label = ""
+					}
+					return configResult(err)
 				},
 				nil,
 			))
@@ -119,18 +111,20 @@ func (s *Moneropay) Init() tea.Msg {
 		})
 	changeAddrButton = NewScreenButton("Update Address", gss.Color(base.CBrightYellow),
 		func(sb *ScreenButton) tea.Cmd {
-			var inp *ScreenInputField
-			inp = NewScreenInputField(addrLabel.label, "address", gss.Color(base.CWhite))
-			AddPopup(NewDefaultPopupYesNo("Restart", "Are you sure?", gss.Color(base.CBrightRed),
-				func(sb *ScreenButton) tea.Cmd {
-					if base.ValidateAddr(inp.Delegate.Value()) {
-						base.SetMpayConfig("deposit_address", inp.Delegate.Value)
-						base.SetMpayConfig("enabled", base.Bool(true))
-					}
-					return nil
-				},
-				nil,
-			))
+			inp := NewScreenInputField(addrLabel.label, "address", gss.Color(base.CWhite))
+			inp.Delegate.Width = 95
+			editConfig("moneropay.deposit_address", "Deposit address",
+				NewDefaultPopupYesNo("Restart", "Are you sure?", gss.Color(base.CBrightRed),
+					func(sb *ScreenButton) tea.Cmd {
+						if err := base.SetMpayConfig("deposit_address", inp.Delegate.Value()); err != nil {
+							return configResult(err)
+						}
+						addrLabel.label = inp.Delegate.Value()
+						return configResult(base.SetMpayConfig("enabled", base.Bool(true)))
+					},
+					nil,
+					inp,
+				), inp)
 			return nil
 		})
 	moneropayPane.Items = append(moneropayPane.Items,
@@ -206,22 +200,22 @@ func (s *Moneropay) View() {
 		if t.Queried {
 			sb.WriteString(fmt.Sprintf("%s\n %s/%s XMR   %s\n",
 				st.Render(shorthandAddress(t.Subaddress, 4, 4)),
-				mpayTxAmountStyle.Render(fmt.Sprintf("%.4f", float64(t.Covered.Unlocked)*float64(10e-12))),
-				mpayTxAmountStyle.Render(fmt.Sprintf("%.4f", float64(t.Expected)*float64(10e-12))),
+				mpayTxAmountStyle.Render(xmr(t.Covered.Unlocked)),
+				mpayTxAmountStyle.Render(xmr(t.Expected)),
 				base.UnixTime(t.CreatedAt.Unix()),
 			))
 			for _, t := range t.TxIds {
 				sb.WriteString(fmt.Sprintf("%s %s  %s XMR\n",
 					t.TxHash,
 					base.UnixTime(t.Timestamp.Unix()),
-					mpayTxAmountStyle.Render(fmt.Sprintf("%.4f", float64(t.Amount)*float64(10e-12))),
+					mpayTxAmountStyle.Render(xmr(t.Amount)),
 				))
 			}
 			sb.WriteString("\n")
 		} else {
 			sb.WriteString(fmt.Sprintf("%s\n ?/%s XMR   %s\n\n",
 				mpayTxAddrStyle.Render(shorthandAddress(t.Subaddress, 4, 4)),
-				mpayTxAmountStyle.Render(fmt.Sprintf("%.4f", float64(t.Expected)*float64(10e-12))),
+				mpayTxAmountStyle.Render(xmr(t.Expected)),
 				base.UnixTime(t.CreatedAt.Unix()),
 			))
 		}
@@ -24,10 +24,6 @@ func getClearnetIp() string {
 
 var node *Node = &Node{}
 
-const (
-	nodePort int = 18089
-)
-
 var (
 	clearnetAddr string
 	onionAddr    string
@@ -60,70 +56,87 @@ func NewNode() *Node {
 var valueStyle = gss.NewStyle().Foreground(gss.Color(base.CWhite))
 
 func newInputIntBtn(label, val string) *ScreenButton {
-	v, _ := base.GetVal(val).(float64)
-	str := fmt.Sprintf("%s: %s", label, valueStyle.Render(strconv.Itoa(int(v))))
 	var btn *ScreenButton
-	btn = NewScreenButton(str, gss.Color(base.CGreen),
+	btn = NewScreenButton("", gss.Color(base.CGreen),
 		func(sb *ScreenButton) tea.Cmd {
 			v, _ := base.GetVal(val).(float64)
 			in := NewScreenInputField(strconv.Itoa(int(v)), strconv.Itoa(int(v)), gss.Color(base.CGray))
-			AddPopup(NewDefaultPopupOKCancel(label, "Set new value", gss.Color(base.CGreen),
+			editConfig(val, label, NewDefaultPopupOKCancel(label, "Set new value", gss.Color(base.CGreen),
 				func(sb *ScreenButton) tea.Cmd {
 					i, err := strconv.Atoi(in.Delegate.Value())
-					if err == nil {
-						base.SetConfig(val, i)
-						base.SaveConfigFile()
+					if err != nil {
+						return configResult(fmt.Errorf("%q is not a number", in.Delegate.Value()))
 					}
-					btn.label = fmt.Sprintf("%s: %s", label, valueStyle.Render(in.Delegate.Value()))
-					return nil
+					if err := base.SetConfig(val, i); err != nil {
+						return configResult(err)
+					}
+					btn.label = fmt.Sprintf("%s: %s", label, valueStyle.Render(strconv.Itoa(i)))
+					return configResult(nil)
 				}, nil,
 				in,
-			))
+			), in)
 			return nil
 		})
+	bindConfig(val, func() {
+		v, _ := base.GetVal(val).(float64)
+		btn.label = fmt.Sprintf("%s: %s", label, valueStyle.Render(strconv.Itoa(int(v))))
+	})
 	return btn
 }
 
 func newInputStrBtn(label, val string, secret bool) *ScreenButton {
-	var (
-		str string
-		btn *ScreenButton
-	)
-	if secret {
-		str = label
-	} else {
-		v, _ := base.GetVal(val).(string)
-		str = fmt.Sprintf("%s: %s", label, valueStyle.Render(v))
-	}
-	btn = NewScreenButton(str, gss.Color(base.CGreen),
+	var btn *ScreenButton
+	btn = NewScreenButton(label, gss.Color(base.CGreen),
 		func(sb *ScreenButton) tea.Cmd {
 			v, _ := base.GetVal(val).(string)
 			in := NewScreenInputField(v, v, gss.Color(base.CGray))
-			AddPopup(NewDefaultPopupOKCancel(label, "Set new value", gss.Color(base.CBrightGreen),
+			editConfig(val, label, NewDefaultPopupOKCancel(label, "Set new value", gss.Color(base.CBrightGreen),
 				func(sb *ScreenButton) tea.Cmd {
-					base.SetConfig(val, in.Delegate.Value())
+					if err := base.SetConfig(val, in.Delegate.Value()); err != nil {
+						return configResult(err)
+					}
 					if !secret {
 						btn.label = fmt.Sprintf("%s: %s", label, valueStyle.Render(in.Delegate.Value()))
 					}
-					return base.SaveConfigFile
+					return configResult(nil)
 				}, nil,
 				in,
-			))
+			), in)
 			return nil
 		})
+	if !secret {
+		bindConfig(val, func() {
+			v, _ := base.GetVal(val).(string)
+			btn.label = fmt.Sprintf("%s: %s", label, valueStyle.Render(v))
+		})
+	}
 	return btn
 }
 
 func newToggle(label, val string) *ScreenToggle {
 	toggle := NewScreenToggle(label, gss.Color(base.CGreen),
 		func(sb *ScreenToggle, toggled bool) tea.Cmd {
-			base.SetConfig(val, toggled)
-			return base.SaveConfigFile
+			err := base.SetConfig(val, toggled)
+			if err != nil {
+				sb.toggled = !toggled
+			}
+			return configResult(err)
 		})
-	toggle.toggled, _ = base.GetVal(val).(bool)
+	bindConfig(val, func() {
+		toggle.toggled, _ = base.GetVal(val).(bool)
+	})
 	return toggle
 }
 
+// Shows why a setting couldn't be staged
+func configResult(err error) tea.Cmd {
+	if err != nil {
+		AddPopup(NewDefaultPopupOK("Couldn't change setting", err.Error(),
+			gss.Color(base.CBrightRed), nil))
+	}
+	return nil
+}
+
 func (s *Node) Init() tea.Msg {
 	hiddenRpcToggle = newToggle("Hidden RPC", "anon_rpc")
 	torToggle = newToggle("Enable Tor", "tor_enabled")
@@ -131,12 +144,18 @@ func (s *Node) Init() tea.Msg {
 	i2pToggle = newToggle("Enable I2P", "i2p_enabled")
 
 	clearnetAddr = getClearnetIp()
-	onionAddr, _ = base.GetVal("tor_address").(string)
-	i2pAddr, _ = base.GetVal("i2p_address").(string)
 
-	clearnetLabel = NewScreenLabel(fmt.Sprintf("%s:%d", clearnetAddr, nodePort), gss.Color(base.CPurple))
-	onionLabel = NewScreenLabel(fmt.Sprintf("%s:%d", onionAddr, nodePort), gss.Color(base.CPurple))
-	i2pLabel = NewScreenLabel(fmt.Sprintf("%s:%d", i2pAddr, nodePort), gss.Color(base.CPurple))
+	clearnetLabel = NewScreenLabel(fmt.Sprintf("%s:%d", clearnetAddr, base.Opts.NodePort), gss.Color(base.CPurple))
+	onionLabel = NewScreenLabel("", gss.Color(base.CPurple))
+	i2pLabel = NewScreenLabel("", gss.Color(base.CPurple))
+	bindConfig("tor_address", func() {
+		onionAddr, _ = base.GetVal("tor_address").(string)
+		onionLabel.label = fmt.Sprintf("%s:%d", onionAddr, base.Opts.NodePort)
+	})
+	bindConfig("i2p_address", func() {
+		i2pAddr, _ = base.GetVal("i2p_address").(string)
+		i2pLabel.label = fmt.Sprintf("%s:%d", i2pAddr, base.Opts.NodePort)
+	})
 
 	clearnetPane = NewScreenPane(
 		"Clearnet",
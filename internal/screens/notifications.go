@@ -0,0 +1,274 @@
+package screens
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/model/dbus"
+)
+
+var notifications *Notifications = &Notifications{}
+
+const (
+	noticeLimit = 500
+	toastTime   = 6 * time.Second
+	toastLimit  = 3
+	toastWidth  = 40
+)
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+var severityNames = []string{"INFO", "WARN", "ERROR"}
+
+var (
+	severityStyles = []gss.Style{
+		SeverityInfo:    gss.NewStyle().Foreground(gss.Color(base.CBrightAqua)),
+		SeverityWarning: gss.NewStyle().Foreground(gss.Color(base.CBrightYellow)),
+		SeverityError:   gss.NewStyle().Foreground(gss.Color(base.CBrightRed)),
+	}
+	toastStyle = gss.NewStyle().Border(gss.RoundedBorder()).Padding(0, 1).Width(toastWidth)
+)
+
+var (
+	noticesLabel       *ScreenLabel
+	noticesClearButton *ScreenButton
+	noticesPane        *ScreenPane
+)
+
+// Something the device told us
+type Notice struct {
+	Time     time.Time
+	Severity Severity
+	Title    string
+	Message  string
+	// Label of the screen the notice is about, empty for none
+	Screen string
+	read   bool
+}
+
+func (n *Notice) String() string {
+	return fmt.Sprintf("%s %-5s %s: %s", n.Time.Format("2006-01-02 15:04:05"),
+		severityNames[n.Severity], n.Title, n.Message)
+}
+
+type toast struct {
+	notice *Notice
+	until  time.Time
+}
+
+type ToastExpiredMsg struct{}
+
+var (
+	notices []*Notice
+	toasts  []toast
+)
+
+// The notice a signal from the embedded interface makes, if any. Status
+// reports come every few seconds and have screens of their own.
+func noticeFor(sig dbus.DbusSignal) (Notice, bool) {
+	switch sig := sig.(type) {
+	case dbus.ServiceManagerNotification:
+		return Notice{Severity: SeverityInfo, Title: "Services", Message: sig.Message, Screen: "Services"}, true
+	case dbus.StartRecoveryNotification:
+		return Notice{Severity: SeverityWarning, Title: "Recovery", Message: sig.Message, Screen: "Recovery"}, true
+	case dbus.FactoryResetRequested:
+		return Notice{Severity: SeverityWarning, Title: "Factory reset",
+			Message: "A factory reset was requested.", Screen: "System"}, true
+	case dbus.FactoryResetStarted:
+		return Notice{Severity: SeverityWarning, Title: "Factory reset",
+			Message: "The factory reset has started.", Screen: "System"}, true
+	case dbus.FactoryResetCompleted:
+		return Notice{Severity: SeverityInfo, Title: "Factory reset",
+			Message: "The factory reset is complete.", Screen: "System"}, true
+	case dbus.ConnectionStatusChanged:
+		return Notice{Severity: SeverityInfo, Title: "Network",
+			Message: "The network connection changed.", Screen: "Dashboard"}, true
+	case dbus.PowerButtonPressDetected:
+		return Notice{Severity: SeverityWarning, Title: "Power button",
+			Message: "The power button was pressed."}, true
+	}
+	return Notice{}, false
+}
+
+// Adds n to the history and shows it as a toast for a while
+func Notify(n Notice) tea.Cmd {
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+	p := &n
+	notices = append(notices, p)
+	notices = notices[max(0, len(notices)-noticeLimit):]
+	toasts = append(toasts, toast{p, n.Time.Add(toastTime)})
+	toasts = toasts[max(0, len(toasts)-toastLimit):]
+
+	log := uiLog.Info
+	if n.Severity >= SeverityWarning {
+		log = uiLog.Warn
+	}
+	log("notice", "title", n.Title, "message", n.Message)
+	return tea.Tick(toastTime, func(time.Time) tea.Msg {
+		return ToastExpiredMsg{}
+	})
+}
+
+// Unread notices about the screen labelled label. The notifications screen
+// counts all of them.
+func Unread(label string) int {
+	var c int
+	for _, n := range notices {
+		if !n.read && (label == notifications.Label() || n.Screen == label) {
+			c++
+		}
+	}
+	return c
+}
+
+func markRead(label string) {
+	for _, n := range notices {
+		if label == notifications.Label() || n.Screen == label {
+			n.read = true
+		}
+	}
+}
+
+// The toasts still showing, stacked, empty when there are none
+func Toasts() string {
+	var ts []string
+	for _, t := range toasts {
+		n := t.notice
+		st := severityStyles[n.Severity]
+		ts = append(ts, toastStyle.BorderForeground(st.GetForeground()).Render(
+			st.Bold(true).Render(n.Title)+"\n"+printable(n.Message)))
+	}
+	return gss.JoinVertical(gss.Right, ts...)
+}
+
+// The history of notices, newest at the bottom
+type Notifications struct {
+	init    bool
+	follow  bool
+	viewing string
+	content []string
+	items   []ScreenItem
+	current int
+}
+
+func NewNotifications() *Notifications {
+	notifications.follow = true
+	return notifications
+}
+
+func (s *Notifications) Init() tea.Msg {
+	noticesLabel = NewScreenLabel("", gss.Color(base.CGray))
+	noticesClearButton = NewScreenButton("Clear", gss.Color(base.CBrightYellow),
+		func(sb *ScreenButton) tea.Cmd {
+			notices, toasts = nil, nil
+			return nil
+		})
+	noticesPane = NewScreenPane("Notifications", gss.Color(base.CBrightAqua),
+		noticesLabel, noticesClearButton)
+	noticesPane.ItemStyle = gss.NewStyle()
+	s.items = append(s.items, noticesPane)
+	s.init = true
+	return nil
+}
+
+func (s *Notifications) Label() string {
+	return "Notifications"
+}
+
+func (s *Notifications) View() {
+	if !s.init {
+		return
+	}
+	s.content = s.content[:0]
+	for _, n := range notices {
+		s.content = append(s.content, severityStyles[n.Severity].Render(printable(n.String())))
+	}
+	if len(s.content) == 0 {
+		s.content = append(s.content, "nothing yet")
+	}
+	noticesLabel.label = fmt.Sprintf("%d notices from the device", len(notices))
+}
+
+func (s *Notifications) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case ScreenActiveChangeMsg:
+		s.viewing = ""
+		if msg.Active {
+			s.viewing = msg.Screen.Label()
+		}
+	case dbus.DbusSignalMsg:
+		if n, ok := noticeFor(msg.Signal); ok {
+			cmd = Notify(n)
+		}
+	case ToastExpiredMsg:
+		now := time.Now()
+		for len(toasts) > 0 && !toasts[0].until.After(now) {
+			toasts = toasts[1:]
+		}
+	}
+	// what's on screen has been seen
+	if s.viewing != "" {
+		markRead(s.viewing)
+	}
+	return cmd
+}
+
+func (s *Notifications) Content() []string {
+	return s.content
+}
+
+func (s *Notifications) Follow() bool {
+	return s.follow
+}
+
+func (s *Notifications) Scrolled(offset, height int) {
+	s.follow = offset+height >= len(s.content)
+}
+
+func (s *Notifications) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *Notifications) Current() *int {
+	return &s.current
+}
+
+func (s *Notifications) Next() tea.Msg {
+	return UpdateFocus(s, 1)
+}
+
+func (s *Notifications) Prev() tea.Msg {
+	return UpdateFocus(s, -1)
+}
+
+func (s *Notifications) Interact(m tea.Model) tea.Cmd {
+	return s.items[s.current].Interact(m)
+}
+
+func (s *Notifications) PosVertical() gss.Position {
+	return gss.Top
+}
+
+func (s *Notifications) PosHorizontal() gss.Position {
+	return gss.Left
+}
+
+func (s *Notifications) ItemWidth() int {
+	return 1
+}
+
+func (s *Notifications) Vertical() bool {
+	return true
+}
@@ -0,0 +1,134 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/base"
+	rpc_model "github.com/moneronodo/sshui/internal/model/daemonrpc"
+)
+
+var peers *Peers = &Peers{}
+
+var (
+	peersLabel *ScreenLabel
+	peersPane  *ScreenPane
+)
+
+var (
+	peerInStyle  = gss.NewStyle().Foreground(gss.Color(base.CBrightGreen))
+	peerOutStyle = gss.NewStyle().Foreground(gss.Color(base.CBrightAqua))
+	peerHdrStyle = gss.NewStyle().Bold(true).Foreground(gss.Color(base.CWhite))
+)
+
+type Peers struct {
+	init        bool
+	connections []rpc_model.GetConnectionsConnection
+	items       []ScreenItem
+	current     int
+}
+
+func NewPeers() *Peers {
+	return peers
+}
+
+func (s *Peers) Init() tea.Msg {
+	peersLabel = NewScreenLabel("", gss.Color(base.CGray))
+	peersPane = NewScreenPane("Connected Peers", gss.Color(base.CBrightAqua), peersLabel)
+	s.items = append(s.items, peersPane)
+	s.init = true
+	return nil
+}
+
+func (s *Peers) Label() string {
+	return "Peers"
+}
+
+const peerRowFmt = " %-22s %-10s %-9s %-10s %-10s %s"
+
+func (s *Peers) View() {
+	if !s.init {
+		return
+	}
+	var (
+		sb      strings.Builder
+		in, out int
+	)
+	for _, c := range s.connections {
+		if c.Incoming {
+			in++
+		} else {
+			out++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("%d outgoing, %d incoming\n\n", out, in))
+	sb.WriteString(peerHdrStyle.Render("Dir" + fmt.Sprintf(peerRowFmt,
+		"Address", "Height", "Live", "Received", "Sent", "State")))
+	sb.WriteString("\n")
+	for _, c := range s.connections {
+		dir := peerOutStyle.Render("OUT")
+		if c.Incoming {
+			dir = peerInStyle.Render("IN ")
+		}
+		sb.WriteString(dir + fmt.Sprintf(peerRowFmt+"\n",
+			c.Address,
+			fmt.Sprint(c.Height),
+			base.FormatSeconds(c.LiveTime),
+			base.FormatBytes(c.RecvCount),
+			base.FormatBytes(c.SendCount),
+			strings.TrimPrefix(c.State, "state_"),
+		))
+	}
+	if len(s.connections) == 0 {
+		sb.WriteString("No peers connected\n")
+	}
+	peersLabel.label = sb.String()
+}
+
+func (s *Peers) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	switch m := msg.(type) {
+	case rpc_model.DaemonRPCMsg[rpc_model.DaemonResponseBodyGetConnections]:
+		if m.Err == nil {
+			s.connections = m.Result.Connections
+		}
+	}
+	return nil
+}
+
+func (s *Peers) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *Peers) Current() *int {
+	return &s.current
+}
+
+func (s *Peers) Next() tea.Msg {
+	return &FocusChangeMsg{}
+}
+
+func (s *Peers) Prev() tea.Msg {
+	return &FocusChangeMsg{}
+}
+
+func (s *Peers) Interact(m tea.Model) tea.Cmd {
+	return nil
+}
+
+func (s *Peers) PosVertical() gss.Position {
+	return gss.Position(0.2)
+}
+
+func (s *Peers) PosHorizontal() gss.Position {
+	return gss.Center
+}
+
+func (s *Peers) ItemWidth() int {
+	return 2
+}
+
+func (s *Peers) Vertical() bool {
+	return true
+}
@@ -0,0 +1,75 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+var pendingStyle = gss.NewStyle().Foreground(gss.Color(base.CBrightYellow))
+
+// Status bar text for the staged settings, empty when there are none
+func PendingStatus() string {
+	n := base.PendingCount()
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return pendingStyle.Render("1 unsaved change, ctrl+s to apply")
+	}
+	return pendingStyle.Render(fmt.Sprintf("%d unsaved changes, ctrl+s to apply", n))
+}
+
+func applyChanges() tea.Cmd {
+	if recoveryBlocks("Applying settings") {
+		return nil
+	}
+	changes, err := base.ApplyChanges()
+	if err != nil {
+		AddPopup(NewDefaultPopupOK("Couldn't save settings", err.Error(),
+			gss.Color(base.CBrightRed), nil))
+		return nil
+	}
+	return func() tea.Msg {
+		return base.ConfigSavedMsg{Services: base.AffectedServices(changes)}
+	}
+}
+
+// Lists the staged settings with the services applying them restarts
+func NewPendingPopup() Popup {
+	changes := base.PendingChanges()
+	if len(changes) == 0 {
+		return NewDefaultPopupOK("No Changes", "There are no unsaved changes.",
+			gss.Color(base.CGray), nil)
+	}
+	body := formatDiff(changes)
+	if services := base.AffectedServices(changes); len(services) > 0 {
+		body += "\n\nRestarts " + strings.Join(services, ", ")
+	}
+	popup := newDefaultPopup("Unsaved Changes", body, gss.Color(base.CBrightYellow))
+	popup.items = []ScreenItem{
+		NewScreenButton("Apply", gss.Color(base.CBrightGreen), func(sb *ScreenButton) tea.Cmd {
+			return applyChanges()
+		}),
+		NewScreenButton("Discard", gss.Color(base.CBrightRed), func(sb *ScreenButton) tea.Cmd {
+			base.DiscardChanges()
+			rebindAll()
+			return nil
+		}),
+		NewScreenButton("Cancel", gss.Color(base.CBrightYellow), nil),
+	}
+	return popup
+}
+
+// Asks before quitting with settings that were never applied
+func NewQuitPopup() Popup {
+	return NewDefaultPopupYesNo("Quit",
+		"Settings that haven't been applied will be lost. Quit anyway?",
+		gss.Color(base.CBrightRed),
+		func(sb *ScreenButton) tea.Cmd {
+			return tea.Quit
+		}, nil)
+}
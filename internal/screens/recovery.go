@@ -0,0 +1,460 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/backend/i_dbus"
+	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/model/dbus"
+)
+
+var recovery *Recovery = &Recovery{}
+
+const recoveryBarWidth = 20
+
+var (
+	recoveryFSToggle     *ScreenToggle
+	recoveryResyncToggle *ScreenToggle
+	recoveryStartButton  *ScreenButton
+	recoveryStopButton   *ScreenButton
+	recoveryStepsLabel   *ScreenLabel
+
+	recoveryOptionsPane *ScreenPane
+	recoveryStepsPane   *ScreenPane
+)
+
+// The Nodo service reports recovery in free text. A message is put down to
+// the first step one of whose keywords it contains, a percentage in it is
+// that step's progress and these words end the step or the run. Counts of
+// nothing, as in "0 errors", are no failure.
+var (
+	recoveryPercent  = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+	recoveryDone     = regexp.MustCompile(`\b(complete|completed|done|finished|success|successful|successfully)\b`)
+	recoveryFailed   = regexp.MustCompile(`\b(fail|fails|failed|failure|failures|error|errors)\b`)
+	recoveryNone     = regexp.MustCompile(`\b(no|0|zero)\s+(errors?|failures?)\b`)
+	recoveryStepDefs = []struct {
+		name     string
+		keywords []string
+	}{
+		{"Filesystem check", []string{"filesystem", "fsck"}},
+		{"Blockchain purge", []string{"purg"}},
+		{"Resync", []string{"resync", "sync"}},
+	}
+)
+
+type stepState int
+
+const (
+	stepSkipped stepState = iota
+	stepPending
+	stepRunning
+	stepDone
+	stepFailed
+)
+
+var (
+	stepStateNames  = []string{"skipped", "pending", "running", "done", "failed"}
+	stepStateColors = []gss.Color{
+		gss.Color(base.CBrightBlack),
+		gss.Color(base.CGray),
+		gss.Color(base.CBrightYellow),
+		gss.Color(base.CBrightGreen),
+		gss.Color(base.CBrightRed),
+	}
+)
+
+type recoveryStep struct {
+	name  string
+	state stepState
+	// percent, negative when not reported
+	progress       float64
+	started, ended time.Time
+}
+
+type recoveryRun struct {
+	started, ended time.Time
+	steps          []*recoveryStep
+	failed         bool
+	log            []string
+	// where the log is kept for later
+	path string
+}
+
+type RecoveryTickMsg struct{}
+
+func recoveryTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return RecoveryTickMsg{}
+	})
+}
+
+func reportsFailure(msg string) bool {
+	return recoveryFailed.MatchString(recoveryNone.ReplaceAllString(msg, ""))
+}
+
+func containsAny(s string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func newRecoveryRun(fs, resync bool) *recoveryRun {
+	r := startRecoveryRun()
+	r.choose(fs, resync)
+	r.record(fmt.Sprintf("recovery started, filesystem check %v, purge and resync %v", fs, resync))
+	return r
+}
+
+// A run not started from here, on the device or by another session. Which
+// steps it takes shows as it goes, until then all of them are pending.
+func adoptRecoveryRun() *recoveryRun {
+	r := startRecoveryRun()
+	r.record("recovery started outside this session")
+	return r
+}
+
+func startRecoveryRun() *recoveryRun {
+	now := time.Now()
+	r := &recoveryRun{
+		started: now,
+		path:    base.LogPath("recovery-" + now.Format("20060102-150405") + ".log"),
+	}
+	for _, def := range recoveryStepDefs {
+		r.steps = append(r.steps, &recoveryStep{name: def.name, state: stepPending, progress: -1})
+	}
+	return r
+}
+
+// Skips the steps the options leave out, unless they got going anyway
+func (r *recoveryRun) choose(fs, resync bool) {
+	for i, st := range r.steps {
+		if st.state == stepPending && !(i == 0 && fs || i > 0 && resync) {
+			st.state = stepSkipped
+		}
+	}
+}
+
+func (r *recoveryRun) running() bool {
+	return r != nil && r.ended.IsZero()
+}
+
+// Adds line to the log on screen and on disk
+func (r *recoveryRun) record(line string) {
+	line = time.Now().Format("15:04:05") + " " + printable(line)
+	r.log = append(r.log, line)
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		uiLog.Error("writing the recovery log failed", "err", err)
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+func (r *recoveryRun) finish(failed bool) {
+	r.ended, r.failed = time.Now(), failed
+	for _, st := range r.steps {
+		switch {
+		case st.state == stepRunning:
+			st.state, st.ended = stepDone, r.ended
+			if failed {
+				st.state = stepFailed
+			}
+		case st.state == stepPending && !failed:
+			// a run that went through without it didn't take it
+			st.state = stepSkipped
+		}
+	}
+	if failed {
+		r.record("recovery failed")
+	} else {
+		r.record("recovery finished")
+	}
+}
+
+// Moves the run along by a notification from the Nodo service
+func (r *recoveryRun) apply(msg string) {
+	r.record(msg)
+	lower := strings.ToLower(msg)
+	now := time.Now()
+	var st *recoveryStep
+	for i, def := range recoveryStepDefs {
+		if containsAny(lower, def.keywords) {
+			st = r.steps[i]
+			break
+		}
+	}
+	if st == nil {
+		// about the run as a whole
+		if strings.Contains(lower, "recovery") {
+			switch {
+			case reportsFailure(lower):
+				r.finish(true)
+			case recoveryDone.MatchString(lower):
+				r.finish(false)
+			}
+		}
+		return
+	}
+	// a step starting means the ones before it are over
+	for _, prev := range r.steps {
+		if prev == st {
+			break
+		}
+		if prev.state == stepRunning {
+			prev.state, prev.ended = stepDone, now
+		}
+	}
+	if st.started.IsZero() {
+		st.started = now
+	}
+	st.state = stepRunning
+	if m := recoveryPercent.FindStringSubmatch(msg); m != nil {
+		st.progress, _ = strconv.ParseFloat(m[1], 64)
+	}
+	switch {
+	case reportsFailure(lower):
+		st.state, st.ended = stepFailed, now
+		r.finish(true)
+	case recoveryDone.MatchString(lower):
+		st.state, st.ended, st.progress = stepDone, now, 100
+		for _, other := range r.steps {
+			if other.state == stepPending || other.state == stepRunning {
+				return
+			}
+		}
+		r.finish(false)
+	}
+}
+
+func elapsed(from, to time.Time) string {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	d := to.Sub(from).Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+func progressBar(pct float64) string {
+	n := int(pct / 100 * recoveryBarWidth)
+	n = min(max(n, 0), recoveryBarWidth)
+	return "[" + strings.Repeat("#", n) + strings.Repeat("-", recoveryBarWidth-n) + "]"
+}
+
+func (r *recoveryRun) render() string {
+	var lines []string
+	for _, st := range r.steps {
+		line := fmt.Sprintf("%-18s %-8s", st.name, stepStateNames[st.state])
+		if !st.started.IsZero() {
+			line += " " + elapsed(st.started, st.ended)
+		}
+		if st.progress >= 0 && st.state != stepSkipped {
+			line += fmt.Sprintf(" %s %5.1f%%", progressBar(st.progress), st.progress)
+		}
+		lines = append(lines, gss.NewStyle().Foreground(stepStateColors[st.state]).Render(line))
+	}
+	status := "running for " + elapsed(r.started, r.ended)
+	switch {
+	case r.running():
+	case r.failed:
+		status = "failed after " + elapsed(r.started, r.ended)
+	default:
+		status = "finished in " + elapsed(r.started, r.ended)
+	}
+	lines = append(lines, "", status, "log: "+r.path)
+	return strings.Join(lines, "\n")
+}
+
+// Tells the user action has to wait while a recovery runs
+func recoveryBlocks(action string) bool {
+	if !recovery.run.running() {
+		return false
+	}
+	AddPopup(NewDefaultPopupOK("Recovery in Progress",
+		action+" has to wait until the recovery is over.", gss.Color(base.CBrightYellow), nil))
+	return true
+}
+
+// Starts a recovery and follows it through the notifications of the Nodo
+// service
+type Recovery struct {
+	init    bool
+	bus     SystemBus
+	run     *recoveryRun
+	follow  bool
+	content []string
+	items   []ScreenItem
+	current int
+}
+
+func NewRecovery(bus SystemBus) *Recovery {
+	recovery.bus = bus
+	recovery.follow = true
+	return recovery
+}
+
+func (s *Recovery) Init() tea.Msg {
+	recoveryFSToggle = NewScreenToggle("Recover Filesystem", gss.Color(base.CYellow), nil)
+	recoveryResyncToggle = NewScreenToggle("Purge & Resync Blockchain", gss.Color(base.CYellow), nil)
+	recoveryStartButton = NewScreenButton("Start Recovery", gss.Color(base.CBrightPurple),
+		func(sb *ScreenButton) tea.Cmd {
+			if recoveryBlocks("Another recovery") {
+				return nil
+			}
+			AddPopup(NewDefaultPopupYesNo("Recovery",
+				"Start the recovery now? The services are stopped while it runs.",
+				gss.Color(base.CBrightRed),
+				func(sb *ScreenButton) tea.Cmd {
+					return i_dbus.CallCmd(s.bus, i_dbus.StartRecovery,
+						recoveryFSToggle.toggled, recoveryResyncToggle.toggled)
+				}, nil))
+			return nil
+		})
+	recoveryStopButton = NewScreenButton("Stop Tracking", gss.Color(base.CYellow),
+		func(sb *ScreenButton) tea.Cmd {
+			AddPopup(NewDefaultPopupYesNo("Stop Tracking",
+				"Treat the recovery as over? This doesn't stop the recovery itself, "+
+					"only unblocks the actions it holds up.",
+				gss.Color(base.CYellow),
+				func(sb *ScreenButton) tea.Cmd {
+					if s.run.running() {
+						s.run.record("tracking stopped by the user")
+						s.run.finish(false)
+					}
+					return nil
+				}, nil))
+			return nil
+		})
+	recoveryStopButton.enabled = false
+	recoveryStepsLabel = NewScreenLabel("", gss.Color(base.CWhite))
+
+	recoveryOptionsPane = NewScreenPane("Recovery", gss.Color(base.CBrightPurple),
+		recoveryFSToggle, recoveryResyncToggle, recoveryStartButton, recoveryStopButton)
+	recoveryStepsPane = NewScreenPane("Progress", gss.Color(base.CAqua), recoveryStepsLabel)
+	s.items = append(s.items, recoveryOptionsPane, recoveryStepsPane)
+	s.init = true
+	return nil
+}
+
+func (s *Recovery) Label() string {
+	return "Recovery"
+}
+
+func (s *Recovery) View() {
+	if !s.init {
+		return
+	}
+	recoveryStopButton.enabled = s.run.running()
+	if s.run == nil {
+		recoveryStepsLabel.label = "No recovery since sshui started."
+		s.content = nil
+		return
+	}
+	recoveryStepsLabel.label = s.run.render()
+	s.content = s.run.log
+}
+
+func (s *Recovery) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case dbus.DbusCallMsg:
+		if msg.Method != i_dbus.StartRecovery.Name {
+			return nil
+		}
+		if msg.Err != nil {
+			AddPopup(NewDefaultPopupOK("Recovery failed", msg.Err.Error(), gss.Color(base.CBrightRed), nil))
+			return nil
+		}
+		fs, resync := recoveryFSToggle.toggled, recoveryResyncToggle.toggled
+		// the first notifications may have beaten the reply
+		if s.run.running() {
+			s.run.choose(fs, resync)
+			return nil
+		}
+		s.run = newRecoveryRun(fs, resync)
+		return recoveryTick()
+	case dbus.DbusSignalMsg:
+		switch sig := msg.Signal.(type) {
+		case dbus.StartRecoveryNotification:
+			var cmd tea.Cmd
+			if !s.run.running() {
+				// not the last words of a run that just ended
+				lower := strings.ToLower(sig.Message)
+				if reportsFailure(lower) || recoveryDone.MatchString(lower) {
+					return nil
+				}
+				s.run = adoptRecoveryRun()
+				cmd = recoveryTick()
+			}
+			s.run.apply(sig.Message)
+			return cmd
+		case dbus.ServiceManagerNotification:
+			// services going down and up along the way
+			if s.run.running() {
+				s.run.record(sig.Message)
+			}
+		}
+	case RecoveryTickMsg:
+		if s.run.running() {
+			return recoveryTick()
+		}
+	}
+	return nil
+}
+
+func (s *Recovery) Content() []string {
+	return s.content
+}
+
+func (s *Recovery) Follow() bool {
+	return s.follow
+}
+
+func (s *Recovery) Scrolled(offset, height int) {
+	s.follow = offset+height >= len(s.content)
+}
+
+func (s *Recovery) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *Recovery) Current() *int {
+	return &s.current
+}
+
+func (s *Recovery) Next() tea.Msg {
+	return UpdateFocus(s, 1)
+}
+
+func (s *Recovery) Prev() tea.Msg {
+	return UpdateFocus(s, -1)
+}
+
+func (s *Recovery) Interact(m tea.Model) tea.Cmd {
+	return s.items[s.current].Interact(m)
+}
+
+func (s *Recovery) PosVertical() gss.Position {
+	return gss.Top
+}
+
+func (s *Recovery) PosHorizontal() gss.Position {
+	return gss.Left
+}
+
+func (s *Recovery) ItemWidth() int {
+	return 2
+}
+
+func (s *Recovery) Vertical() bool {
+	return false
+}
@@ -0,0 +1,156 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/backend/i_systemd"
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+const (
+	jobTimeout  = 2 * time.Minute
+	jobLogLines = 5
+	jobLogWidth = 100
+)
+
+var (
+	jobOkStyle   = gss.NewStyle().Foreground(gss.Color(base.CBrightGreen))
+	jobFailStyle = gss.NewStyle().Foreground(gss.Color(base.CBrightRed))
+	jobWaitStyle = gss.NewStyle().Foreground(gss.Color(base.CBrightYellow))
+	jobLogStyle  = gss.NewStyle().Foreground(gss.Color(base.CBrightBlack))
+)
+
+// What a job does to a service, in the forms the popup needs
+type jobVerb struct {
+	name, doing, done string
+	run               func(m ServiceManager, ctx context.Context, unit string) error
+}
+
+var (
+	jobStart   = jobVerb{"Start", "Starting", "Started", ServiceManager.Start}
+	jobStop    = jobVerb{"Stop", "Stopping", "Stopped", ServiceManager.Stop}
+	jobRestart = jobVerb{"Restart", "Restarting", "Restarted", ServiceManager.Restart}
+)
+
+type serviceResult struct {
+	done bool
+	err  error
+	log  []string
+}
+
+// Services started, stopped or restarted one after the other, shown in a
+// popup as they go
+type serviceRun struct {
+	manager  ServiceManager
+	verb     jobVerb
+	services []string
+	results  []serviceResult
+	popup    *DefaultPopup
+}
+
+// One service of a run has finished
+type ServiceJobMsg struct {
+	run    *serviceRun
+	index  int
+	result serviceResult
+}
+
+func runServices(manager ServiceManager, verb jobVerb, services []string) tea.Cmd {
+	if len(services) == 0 {
+		return nil
+	}
+	r := &serviceRun{
+		manager:  manager,
+		verb:     verb,
+		services: services,
+		results:  make([]serviceResult, len(services)),
+		popup:    NewDefaultPopupOK(verb.doing+" Services", "", gss.Color(base.CBrightYellow), nil),
+	}
+	r.render()
+	AddPopup(r.popup)
+	return r.run(0)
+}
+
+func RestartServices(manager ServiceManager, services []string) tea.Cmd {
+	return runServices(manager, jobRestart, services)
+}
+
+func (r *serviceRun) run(i int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+		defer cancel()
+		res := serviceResult{done: true}
+		res.err = r.verb.run(r.manager, ctx, r.services[i])
+		if res.err != nil {
+			systemdLog.Error(strings.ToLower(r.verb.name)+" failed", "unit", r.services[i], "err", res.err)
+		}
+		log, err := r.manager.Journal(r.services[i], jobLogLines)
+		if err != nil {
+			systemdLog.Warn("reading journal failed", "unit", r.services[i], "err", err)
+		}
+		res.log = log
+		return ServiceJobMsg{r, i, res}
+	}
+}
+
+func (r *serviceRun) render() {
+	var (
+		sb     strings.Builder
+		failed bool
+		done   = true
+	)
+	for i, svc := range r.services {
+		res := r.results[i]
+		var (
+			state string
+			je    *i_systemd.JobError
+		)
+		switch {
+		case errors.As(res.err, &je):
+			failed = true
+			state = jobFailStyle.Render(je.Result)
+		case res.err != nil:
+			failed = true
+			state = jobFailStyle.Render("failed: " + res.err.Error())
+		case res.done:
+			state = jobOkStyle.Render(strings.ToLower(r.verb.done))
+		case i == 0 || r.results[i-1].done:
+			done = false
+			state = jobWaitStyle.Render(strings.ToLower(r.verb.doing) + "...")
+		default:
+			done = false
+			state = jobLogStyle.Render("waiting")
+		}
+		sb.WriteString(fmt.Sprintf("%-12s %s\n", svc, state))
+		for _, l := range res.log {
+			if len(l) > jobLogWidth {
+				l = l[:jobLogWidth-3] + "..."
+			}
+			sb.WriteString(jobLogStyle.Render("  "+l) + "\n")
+		}
+	}
+	switch {
+	case done && failed:
+		r.popup.title = r.verb.name + " Failed"
+	case done:
+		r.popup.title = "Services " + r.verb.done
+	}
+	r.popup.body = strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Records the result and moves on to the next service
+func (msg ServiceJobMsg) Continue() tea.Cmd {
+	r := msg.run
+	r.results[msg.index] = msg.result
+	r.render()
+	if msg.index+1 < len(r.services) {
+		return r.run(msg.index + 1)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package screens
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -16,7 +17,10 @@ var (
 
 type ScreenToggleAction func(*ScreenToggle, bool) tea.Cmd
 type ScreenButtonAction func(*ScreenButton) tea.Cmd
+type ScreenListAction func(*ScreenList, int) tea.Cmd
 
+// Sent when a screen is entered or left, and when the tabs move on to
+// another one, which is then in view without being active
 type ScreenActiveChangeMsg struct{
 	Active bool
 	Screen Screen
@@ -71,6 +75,17 @@ type Screen interface {
 	Interact(model tea.Model) tea.Cmd
 }
 
+// A screen with more lines than fit. They are shown under its items and
+// scrolled with pgup/pgdown.
+type ScrollScreen interface {
+	Screen
+	Content() []string
+	// Keep the last line in view
+	Follow() bool
+	// Called with the lines in view after every update
+	Scrolled(offset, height int)
+}
+
 type ScreenItem interface {
 	Render() string
 	IsEnabled() bool
@@ -437,6 +452,138 @@ func (sb *ScreenButton) GetColor() gss.Color {
 	return sb.color
 }
 
+// Scrollable list of rows. The owning screen forwards up/down to Scroll
+// while the list is focused and falls back to moving focus at the edges.
+type ScreenList struct {
+	rows        []string
+	cursor      int
+	offset      int
+	height      int
+	focus       bool
+	enabled     bool
+	Action      ScreenListAction
+	color       gss.Color
+	Style       gss.Style
+	StyleCursor gss.Style
+}
+
+func NewScreenList(height int, color gss.Color, action ScreenListAction) *ScreenList {
+	sl := new(ScreenList)
+	sl.height = max(1, height)
+	sl.SetColor(color)
+	sl.Action = action
+	sl.enabled = true
+	return sl
+}
+
+func (sl *ScreenList) SetRows(rows []string) {
+	sl.rows = rows
+	sl.cursor = max(0, min(sl.cursor, len(rows)-1))
+	sl.offset = max(0, min(sl.offset, len(rows)-sl.height))
+}
+
+func (sl *ScreenList) Rows() []string {
+	return sl.rows
+}
+
+func (sl *ScreenList) Cursor() int {
+	return sl.cursor
+}
+
+// Moves the cursor by mod rows, returns false if it would leave the list.
+func (sl *ScreenList) Scroll(mod int) bool {
+	c := sl.cursor + mod
+	if c < 0 || c >= len(sl.rows) {
+		return false
+	}
+	sl.SetCursor(c)
+	return true
+}
+
+func (sl *ScreenList) SetCursor(c int) {
+	sl.cursor = max(0, min(c, len(sl.rows)-1))
+	if sl.cursor < sl.offset {
+		sl.offset = sl.cursor
+	} else if sl.cursor >= sl.offset+sl.height {
+		sl.offset = sl.cursor - sl.height + 1
+	}
+}
+
+func (sl *ScreenList) Update(msg tea.Msg, _ tea.Model) tea.Cmd {
+	if !sl.focus {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "pgup":
+			sl.SetCursor(sl.cursor - sl.height)
+		case "pgdown":
+			sl.SetCursor(sl.cursor + sl.height)
+		case "home":
+			sl.SetCursor(0)
+		case "end":
+			sl.SetCursor(len(sl.rows) - 1)
+		}
+	}
+	return nil
+}
+
+// Keeps the cursor in place after the action instead of advancing focus.
+func (sl *ScreenList) Interact(m tea.Model) tea.Cmd {
+	var cmd tea.Cmd
+	if sl.Action != nil && len(sl.rows) > 0 {
+		cmd = sl.Action(sl, sl.cursor)
+	}
+	if cmd == nil {
+		cmd = func() tea.Msg {
+			return FocusChangeMsg{Current: sl}
+		}
+	}
+	return cmd
+}
+
+func (sl *ScreenList) Render() string {
+	var s []string
+	end := min(sl.offset+sl.height, len(sl.rows))
+	for i := sl.offset; i < end; i++ {
+		if sl.focus && i == sl.cursor {
+			s = append(s, sl.StyleCursor.Render(sl.rows[i]))
+		} else {
+			s = append(s, sl.Style.Render(sl.rows[i]))
+		}
+	}
+	for i := end - sl.offset; i < sl.height; i++ {
+		s = append(s, "")
+	}
+	if len(sl.rows) > 0 {
+		s = append(s, sl.Style.Faint(true).Render(fmt.Sprintf("%d/%d", sl.cursor+1, len(sl.rows))))
+	}
+	return gss.JoinVertical(gss.Left, s...)
+}
+
+func (sl *ScreenList) SetFocus(focus bool) {
+	sl.focus = focus
+}
+
+func (sl *ScreenList) IsFocus() bool {
+	return sl.focus
+}
+
+func (sl *ScreenList) IsEnabled() bool {
+	return sl.enabled && len(sl.rows) > 0
+}
+
+func (sl *ScreenList) SetColor(color gss.Color) {
+	sl.color = color
+	sl.Style = gss.NewStyle().Foreground(sl.color)
+	sl.StyleCursor = gss.NewStyle().Foreground(gss.Color(base.CBlack)).Background(sl.color)
+}
+
+func (sl *ScreenList) GetColor() gss.Color {
+	return sl.color
+}
+
 func enabledItems(items []ScreenItem) []int {
 	en := []int{}
 	for i, v := range items {
@@ -662,9 +809,7 @@ func (dp *DefaultPopup) Interact(model tea.Model) tea.Cmd {
 func (dp *DefaultPopup) Width() int {
 	var minwidth int = len(dp.title)
 	for s := range strings.SplitSeq(dp.body, "\n") {
-		if minwidth < len(s) {
-			minwidth = len(s)
-		}
+		minwidth = max(minwidth, gss.Width(s))
 	}
 	for _, i := range dp.Items() {
 		switch i := i.(type) {
@@ -707,3 +852,13 @@ func AddPopup(popup Popup) {
 	SetFocusPopup(popup)
 	Popups = append([]Popup{popup}, Popups...)
 }
+
+// Closes p, which needn't be on top anymore if its action opened another
+func ClosePopup(p Popup) {
+	Popups = slices.DeleteFunc(Popups, func(q Popup) bool {
+		return q == p
+	})
+	if len(Popups) == 0 {
+		Popups = nil
+	}
+}
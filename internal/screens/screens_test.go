@@ -0,0 +1,756 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moneronodo/sshui/internal/backend/i_dbus"
+	"github.com/moneronodo/sshui/internal/backend/i_moneropay"
+	"github.com/moneronodo/sshui/internal/backend/i_systemd"
+	"github.com/moneronodo/sshui/internal/base"
+	rpc_model "github.com/moneronodo/sshui/internal/model/daemonrpc"
+	dbus_model "github.com/moneronodo/sshui/internal/model/dbus"
+	"github.com/moneronodo/sshui/internal/model/lws"
+	"github.com/moneronodo/sshui/internal/model/moneropay"
+)
+
+// A point in time the given distance back from now, with room for the
+// test to run before relative times such as "2 hours ago" change
+func ago(d time.Duration) int64 {
+	return time.Now().Add(-d - 30*time.Second).Unix()
+}
+
+func hash(c byte) string {
+	return strings.Repeat(string(c), 64)
+}
+
+// The height of the fake chain
+const top = 3_200_000
+
+func header(height uint64, age time.Duration) rpc_model.BlockHeader {
+	return rpc_model.BlockHeader{
+		Depth:        top - height,
+		Hash:         hash("0123456789abcdef"[height%16]),
+		Height:       height,
+		Timestamp:    ago(age),
+		BlockSize:    40_000 + height%7*1_000,
+		BlockWeight:  41_000,
+		NumTxes:      uint(height % 20),
+		Reward:       600_000_000_000,
+		Difficulty:   300_000_000_000,
+		MajorVersion: 16,
+		MinorVersion: 16,
+		PrevHash:     hash('f'),
+		MinerTxHash:  hash('e'),
+	}
+}
+
+// A synchronised node at height 3200000 with a few peers, bans and
+// transactions in its pool
+func newFakeChain() *fakeDaemon {
+	d := newFakeDaemon()
+	d.results["get_info"] = rpc_model.DaemonResponseBodyGetInfo{
+		Height:                   top,
+		TargetHeight:             top,
+		Synchronized:             true,
+		Version:                  "0.18.3.4-release",
+		OutgoingConnectionsCount: 2,
+		IncomingConnectionsCount: 1,
+		Nettype:                  "mainnet",
+		Mainnet:                  true,
+		DatabaseSize:             230 << 30,
+		FreeSpace:                3 << 40,
+		TxPoolSize:               3,
+		StartTime:                int(ago(26 * time.Hour)),
+		WhitePeerlistSize:        1000,
+		GreyPeerlistSize:         5000,
+		Status:                   "OK",
+	}
+	d.results["get_version"] = rpc_model.DaemonResponseBodyGetVersion{
+		Version: 0x10010, CurrentHeight: top, Release: true, Status: "OK",
+	}
+	d.results["get_connections"] = rpc_model.DaemonResponseBodyGetConnections{
+		Connections: []rpc_model.GetConnectionsConnection{
+			{Address: "198.51.100.7:18080", Height: top, LiveTime: 3_700, RecvCount: 12 << 20, SendCount: 3 << 20, State: "state_normal"},
+			{Address: "203.0.113.40:18080", Height: top - 1, LiveTime: 90_000, RecvCount: 800 << 10, SendCount: 2 << 20, State: "state_synchronizing"},
+			{Address: "192.0.2.12:51234", Height: top, LiveTime: 59, RecvCount: 900, SendCount: 1_500, State: "state_normal", Incoming: true},
+		},
+		Status: "OK",
+	}
+	d.results["get_bans"] = rpc_model.DaemonResponseBodyGetBans{
+		Bans: []rpc_model.GetBansBan{
+			{Host: "198.51.100.66", Seconds: 86_000},
+			{Host: "203.0.113.0/24", Seconds: 3_000},
+		},
+		Status: "OK",
+	}
+	d.results["set_bans"] = rpc_model.DaemonResponseBodySetBans{Status: "OK"}
+	d.results["get_last_block_header"] = rpc_model.DaemonResponseBodyGetLastBlockHeader{
+		BlockHeader: header(top, 3*time.Hour), Status: "OK",
+	}
+	d.results["get_block_headers_range"] = func(params any) any {
+		p := params.(rpc_model.DaemonRequestParamsGetBlockHeadersRange)
+		r := rpc_model.DaemonResponseBodyGetBlockHeadersRange{Status: "OK"}
+		for h := p.StartHeight; h <= p.EndHeight; h++ {
+			r.Headers = append(r.Headers, header(h, time.Duration(top-h+3)*time.Hour))
+		}
+		return r
+	}
+	d.results["get_block_header_by_height"] = func(params any) any {
+		p := params.(rpc_model.DaemonRequestParamsGetBlockHeaderByHeight)
+		if p.Height > top {
+			return rpc_model.DaemonResponseBodyGetBlockHeader{Status: "Requested block height too big"}
+		}
+		return rpc_model.DaemonResponseBodyGetBlockHeader{
+			BlockHeader: header(p.Height, time.Duration(top-p.Height+3)*time.Hour), Status: "OK",
+		}
+	}
+	d.results["get_transaction_pool_stats"] = rpc_model.DaemonResponseBodyGetTransactionPoolStats{
+		PoolStats: rpc_model.TxPoolStats{
+			TxsTotal:   3,
+			BytesTotal: 6_000,
+			FeeTotal:   90_000_000,
+			Oldest:     ago(2 * time.Hour),
+			Num10m:     2,
+		},
+		Status: "OK",
+	}
+	d.results["get_transaction_pool"] = rpc_model.DaemonResponseBodyGetTransactionPool{
+		Transactions: []rpc_model.TxPoolTransaction{
+			{IdHash: hash('a'), Fee: 60_000_000, Weight: 1_500, BlobSize: 1_500, ReceiveTime: ago(2 * time.Hour), Relayed: true},
+			{IdHash: hash('b'), Fee: 20_000_000, Weight: 2_500, BlobSize: 2_500, ReceiveTime: ago(time.Hour), Relayed: true},
+			{IdHash: hash('c'), Fee: 10_000_000, Weight: 2_000, BlobSize: 2_000, ReceiveTime: ago(3 * time.Hour)},
+		},
+		Status: "OK",
+	}
+	d.results["flush_txpool"] = func(any) any {
+		d.results["get_transaction_pool"] = rpc_model.DaemonResponseBodyGetTransactionPool{Status: "OK"}
+		d.results["get_transaction_pool_stats"] = rpc_model.DaemonResponseBodyGetTransactionPoolStats{Status: "OK"}
+		return rpc_model.DaemonResponseBodyFlushTxpool{Status: "OK"}
+	}
+	d.results["get_peer_list"] = rpc_model.DaemonResponseBodyGetPeerList{
+		WhiteList: make([]rpc_model.PeerListPeer, 1000),
+		GrayList:  make([]rpc_model.PeerListPeer, 5000),
+		Status:    "OK",
+	}
+	d.results["get_net_stats"] = rpc_model.DaemonResponseBodyGetNetStats{
+		StartTime:     ago(26 * time.Hour),
+		TotalBytesIn:  40 << 30,
+		TotalBytesOut: 12 << 30,
+		Status:        "OK",
+	}
+	return d
+}
+
+// Absolute times are shown in the configured zone, they move with now
+const absTime = `\d{1,2} [A-Z][a-z]+ \d{4} \d\d:\d\d:\d\d`
+
+func TestDashboard(t *testing.T) {
+	*dash = Dashboard{}
+	h := open(t, NewDashboard())
+	h.golden("empty")
+
+	daemon := newFakeChain()
+	_updateRpc(h.send, daemon)
+	h.send(dbus_model.DbusSignalMsg{Signal: dbus_model.HardwareStatusReadyNotification{
+		Message: "23.0\n1.6\n3.10\n7.76\n51.2\n0.231\n3.638\n9.4\n58.2\n3 days, 4:17",
+	}})
+	h.send(dbus_model.DbusSignalMsg{Signal: dbus_model.ServiceStatusReadyNotification{
+		Message: "monerod:active\ntor:active\ni2pd:inactive\nmonero-lws:active\nsshd:active\nmoneropay:failed",
+	}})
+	h.golden("synchronized")
+
+	daemon.errs["get_info"] = &rpc_model.DaemonConnectionRefusedErr{}
+	_updateRpc(h.send, daemon)
+	h.mask(`since \d\d:\d\d`, "since hh:mm")
+	h.golden("unreachable")
+}
+
+func TestPeers(t *testing.T) {
+	*peers = Peers{}
+	h := open(t, NewPeers())
+	h.golden("none")
+
+	_updateRpc(h.send, newFakeChain())
+	h.golden("connected")
+}
+
+func TestBans(t *testing.T) {
+	*bans = Bans{}
+	daemon := newFakeChain()
+	h := open(t, NewBans(daemon))
+	_updateRpc(h.send, daemon)
+	h.golden("listed")
+	h.enter()
+
+	// with no bans at first the host field has the focus, enter on a
+	// button that only opens a popup moves on as well
+	h.press("down", "down", "enter")
+	h.golden("no host")
+	h.press("enter")
+
+	h.press("down", "down", "1.2.3.4", "down", "backspace", "backspace", "9000", "down", "enter")
+	h.golden("too long")
+	h.press("enter")
+
+	daemon.results["get_bans"] = rpc_model.DaemonResponseBodyGetBans{
+		Bans: []rpc_model.GetBansBan{
+			{Host: "198.51.100.66", Seconds: 86_000},
+			{Host: "203.0.113.0/24", Seconds: 3_000},
+			{Host: "1.2.3.4", Seconds: 7_200},
+		},
+		Status: "OK",
+	}
+	h.press("down", "down", "down", "backspace", "backspace", "backspace", "backspace", "2", "down", "enter")
+	if got := fmt.Sprint(daemon.called("set_bans")); !strings.Contains(got, "1.2.3.4 true 7200") {
+		t.Errorf("set_bans called with %s", got)
+	}
+	h.golden("banned")
+
+	daemon.errs["set_bans"] = errors.New("connection reset")
+	// round to the bans, the new one is last
+	h.press("down", "down", "down", "enter")
+	h.golden("unban")
+	h.press("enter")
+	h.golden("unban failed")
+}
+
+func TestBlocks(t *testing.T) {
+	*blocks = Blocks{}
+	daemon := newFakeChain()
+	h := open(t, NewBlocks(daemon))
+	h.mask(absTime, "<time>")
+	_updateRpc(h.send, daemon)
+	h.golden("recent")
+	h.enter()
+
+	h.press("3199990", "down", "enter")
+	h.golden("found")
+	h.press("enter", "up")
+
+	for range len("3199990") {
+		h.press("backspace")
+	}
+	h.press("9999999", "down", "enter")
+	h.golden("not found")
+	h.press("enter", "up")
+
+	for range len("9999999") {
+		h.press("backspace")
+	}
+	h.press("nope", "down", "enter")
+	h.golden("invalid")
+}
+
+func TestTxPool(t *testing.T) {
+	*txPool = TxPool{}
+	daemon := newFakeChain()
+	h := open(t, NewTxPool(daemon))
+	_updateRpc(h.send, daemon)
+	h.golden("pool")
+	h.enter()
+
+	h.press("down", "down")
+	h.press("enter")
+	h.mask(absTime, "<time>")
+	h.golden("details")
+	h.press("enter")
+
+	h.press("up", "up", "enter")
+	h.golden("flush")
+	h.press("enter")
+	h.golden("flushed")
+}
+
+// Staged changes as key: old -> new
+func pendingChanges() string {
+	var sb strings.Builder
+	for _, c := range base.PendingChanges() {
+		fmt.Fprintf(&sb, "%s: %v -> %v\n", c.Key, c.Old, c.New)
+	}
+	return sb.String()
+}
+
+func TestPendingStatus(t *testing.T) {
+	restoreConfig(t)
+	status := func(want string) {
+		t.Helper()
+		if got := PendingStatus(); !strings.Contains(got, want) || want == "" && got != "" {
+			t.Errorf("status %q, want %q", got, want)
+		}
+	}
+	status("")
+	for _, s := range []struct {
+		key  string
+		v    int
+		want string
+	}{
+		{"in_peers", 48, "1 unsaved change,"},
+		{"out_peers", 16, "2 unsaved changes"},
+		{"in_peers", 32, "1 unsaved change,"},
+	} {
+		if err := base.SetConfig(s.key, s.v); err != nil {
+			t.Fatal(err)
+		}
+		status(s.want)
+	}
+	if _, err := base.ApplyChanges(); err != nil {
+		t.Fatal(err)
+	}
+	status("")
+
+	// staged back to 12, then the file says 12 too
+	if err := base.SetConfig("out_peers", 12); err != nil {
+		t.Fatal(err)
+	}
+	status("1 unsaved change,")
+	if err := writeConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := base.ReloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	status("")
+
+	if err := base.SetConfig("in_peers", 48); err != nil {
+		t.Fatal(err)
+	}
+	base.DiscardChanges()
+	status("")
+}
+
+func TestNode(t *testing.T) {
+	*node = Node{}
+	restoreConfig(t)
+	h := open(t, NewNode())
+	// whichever interface leads to the LAN
+	h.maskCell(`\d+\.\d+\.\d+\.\d+:\d+ *`, "<lan address>")
+	h.golden("addresses")
+	h.enter()
+
+	h.press("enter")
+	if got := pendingChanges(); got != "anon_rpc: FALSE -> TRUE\n" {
+		t.Errorf("pending changes:\n%s", got)
+	}
+	h.golden("hidden rpc")
+}
+
+func TestSettings(t *testing.T) {
+	*settings = Settings{}
+	restoreConfig(t)
+	h := open(t, NewSettings())
+	h.golden("values")
+	h.enter()
+
+	h.press("enter")
+	h.golden("edit peers")
+	RebindConfig([]base.ConfigChange{{Key: "in_peers", Old: 32.0, New: 1_000_000.0}})
+	h.golden("changed elsewhere")
+	h.press("enter")
+	h.golden("loaded")
+	h.press("esc")
+
+	h.press("down", "down", "down", "down", "down", "down", "enter")
+	h.golden("banlists")
+	h.press("down", "enter")
+	if got := pendingChanges(); got != "banlists.dns: TRUE -> FALSE\n" {
+		t.Errorf("pending changes:\n%s", got)
+	}
+	h.golden("dns off")
+}
+
+// Saves the config with in_peers at n, the snapshot of what it replaced
+// is dated at
+func saveConfig(t *testing.T, n int, at time.Time) {
+	t.Helper()
+	if err := base.SetConfig("in_peers", n); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := base.ApplyChanges(); err != nil {
+		t.Fatal(err)
+	}
+	snaps, err := base.Snapshots()
+	if err != nil || len(snaps) == 0 {
+		t.Fatal("no snapshot", err)
+	}
+	name := fmt.Sprintf("config-%s.json", at.Format("20060102T150405.000000000"))
+	if err := os.Rename(snaps[0].Path, filepath.Join(filepath.Dir(snaps[0].Path), name)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	*history = History{}
+	recovery.run = nil
+	restoreConfig(t)
+	h := open(t, NewHistory())
+	h.golden("none")
+
+	saveConfig(t, 48, time.Date(2026, 3, 14, 9, 26, 0, 0, time.UTC))
+	// big enough for fmt to print it as 1e+06
+	if err := base.SetConfig("limit_rate_up", 1_000_000); err != nil {
+		t.Fatal(err)
+	}
+	saveConfig(t, 64, time.Date(2026, 3, 15, 18, 0, 0, 0, time.UTC))
+	h.send(base.ConfigSavedMsg{})
+	// the relative time is padded to 16 in the row
+	h.mask(`(\d\d:\d\d:\d\d)  .{16} `, fmt.Sprintf("$1  %-16s ", "<relative>"))
+	h.enter()
+	h.golden("saved")
+
+	h.press("down")
+	h.golden("older")
+	h.press("enter")
+	h.golden("roll back")
+	h.press("enter")
+	if v, _ := base.GetVal("in_peers").(float64); v != 32 {
+		t.Errorf("in_peers is %v after rolling back", v)
+	}
+}
+
+func TestSystem(t *testing.T) {
+	*system = System{}
+	recovery.run = nil
+	t.Cleanup(func() { reset = nil })
+	bus := newFakeBus()
+	h := open(t, NewSystem(bus))
+	h.golden("power")
+	h.enter()
+
+	h.press("enter")
+	h.golden("reboot")
+	h.press("enter")
+	h.golden("rebooting")
+	h.press("enter")
+
+	bus.errs[i_dbus.FactoryReset.Name] = errors.New("wrong password")
+	h.press("down", "erase my nodo", "down", "hunter2", "down")
+	h.golden("reset armed")
+	h.press("enter", "enter")
+	h.golden("reset failed")
+	h.press("enter")
+
+	delete(bus.errs, i_dbus.FactoryReset.Name)
+	h.press("down", "down", "erase my nodo", "down", "hunter2", "down", "enter", "enter")
+	h.mask(`\b\d:\d\d:\d\d\b`, "h:mm:ss")
+	h.golden("resetting")
+	h.send(dbus_model.DbusSignalMsg{Signal: dbus_model.FactoryResetStarted{}})
+	h.send(dbus_model.DbusSignalMsg{Signal: dbus_model.FactoryResetCompleted{}})
+	h.golden("reset complete")
+	var resets []any
+	for _, c := range bus.called() {
+		if c.method == i_dbus.FactoryReset.Name {
+			resets = append(resets, c.args)
+		}
+	}
+	if got := fmt.Sprint(resets); got != "[[hunter2] [hunter2]]" {
+		t.Errorf("factoryReset called with %s", got)
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	*recovery = Recovery{}
+	t.Cleanup(func() { recovery.run = nil })
+	bus := newFakeBus()
+	h := open(t, NewRecovery(bus))
+	h.mask(`\b\d\d:\d\d:\d\d\b`, "hh:mm:ss")
+	h.mask(`\b\d:\d\d:\d\d\b`, "h:mm:ss")
+	h.mask(`\d{8}-\d{6}`, "yyyymmdd-hhmmss")
+	h.golden("idle")
+	h.enter()
+
+	// both options, on to Start
+	h.press("enter", "enter", "enter")
+	h.golden("confirm")
+	h.press("enter")
+	if got := fmt.Sprint(bus.called()); got != "[{startRecovery [true true]}]" {
+		t.Errorf("calls %s", got)
+	}
+	notify := func(msg string) {
+		h.send(dbus_model.DbusSignalMsg{Signal: dbus_model.StartRecoveryNotification{Message: msg}})
+	}
+	notify("Filesystem check 40%")
+	h.golden("checking")
+
+	notify("Filesystem check completed, 0 errors")
+	notify("Purging blockchain")
+	h.send(dbus_model.DbusSignalMsg{Signal: dbus_model.ServiceManagerNotification{Message: "monerod stopped"}})
+	notify("Resync 12.5%")
+	h.golden("resyncing")
+
+	notify("Resync failed: disk full")
+	h.golden("failed")
+
+	// one started on the device
+	notify("Filesystem check started")
+	h.golden("adopted")
+	h.press("enter")
+	h.golden("blocked")
+}
+
+func TestServices(t *testing.T) {
+	*services = Services{}
+	recovery.run = nil
+	units := newFakeUnits()
+	up := i_systemd.UnitStatus{
+		ActiveState: "active", SubState: "running", UnitFileState: "enabled",
+		ActiveSince: time.Now().Add(-26*time.Hour - 10*time.Minute), Memory: 300 << 20,
+	}
+	for _, u := range serviceUnits {
+		units.status[u] = up
+	}
+	units.status["i2pd"] = i_systemd.UnitStatus{ActiveState: "inactive", SubState: "dead", UnitFileState: "disabled"}
+	units.status["moneropay"] = i_systemd.UnitStatus{ActiveState: "failed", SubState: "failed", UnitFileState: "enabled", Restarts: 3}
+	delete(units.status, "monero-lws")
+	// read once the tab is on it
+	h := open(t, NewServices(units))
+	h.golden("statuses")
+	h.enter()
+
+	// i2pd, then the actions for it
+	h.press("down", "down", "enter", "enter")
+	h.golden("start")
+	h.press("enter")
+	h.golden("started")
+	h.press("enter")
+
+	units.errs["enable"] = errors.New("access denied")
+	h.press("down", "down", "enter", "enter")
+	h.golden("enable failed")
+	if got := fmt.Sprint(units.calls); got != "[{start i2pd} {enable i2pd}]" {
+		t.Errorf("calls %s", got)
+	}
+}
+
+func TestLogs(t *testing.T) {
+	*logs = Logs{}
+	units := newFakeUnits()
+	at := time.Date(2026, 3, 14, 9, 26, 0, 0, time.UTC)
+	entry := func(d time.Duration, prio int, msg string) i_systemd.JournalEntry {
+		return i_systemd.JournalEntry{Time: at.Add(d), Host: "nodo", Ident: "monerod", Pid: "812", Priority: prio, Message: msg}
+	}
+	units.journal["monerod"] = []i_systemd.JournalEntry{
+		entry(0, i_systemd.PrioInfo, "Synchronized with the network"),
+		entry(time.Second, i_systemd.PrioDebug, "Connected to peer 198.51.100.7"),
+		entry(2*time.Second, i_systemd.PrioWarning, "Failed to connect to peer 203.0.113.9"),
+		entry(3*time.Second, i_systemd.PrioErr, "Error opening \x1b[31mdatabase\x1b[0m"),
+	}
+	units.journal["tor"] = []i_systemd.JournalEntry{
+		{Time: at, Host: "nodo", Ident: "tor", Pid: "640", Priority: i_systemd.PrioNotice, Message: "Bootstrapped 100% (done): Done"},
+	}
+	h := open(t, NewLogs(units))
+	h.golden("empty")
+	h.enter()
+	h.golden("monerod")
+
+	h.press("down", "down", "down", "PEER")
+	h.golden("search")
+	h.press("down", "enter")
+	h.mask(`\d{8}-\d{6}`, "yyyymmdd-hhmmss")
+	h.golden("exported")
+	h.press("enter")
+
+	h.press("up", "up", "backspace", "backspace", "backspace", "backspace", "up", "up", "enter")
+	h.golden("debug")
+	h.press("up", "enter")
+	h.golden("tor")
+
+	// only what is left of the escapes is searched
+	h.press("enter", "enter", "enter", "enter", "down", "down", "down", "[31m")
+	h.golden("escape bytes")
+	h.press("backspace", "backspace", "backspace", "backspace", "opening database")
+	h.golden("across escapes")
+
+	// a slow read holds the ticks back
+	read := logs.Update(LogsTickMsg{}, nil)
+	if read == nil {
+		t.Fatal("following, but a tick doesn't read the journal")
+	}
+	if logs.Update(LogsTickMsg{}, nil) != nil {
+		t.Error("a tick reads the journal while a read is out")
+	}
+	logs.Update(JournalMsg{Unit: "tor", Priority: i_systemd.PrioDebug}, nil)
+	if logs.Update(LogsTickMsg{}, nil) != nil {
+		t.Error("a read for another unit ends the one that is out")
+	}
+	h.send(read())
+	if logs.Update(LogsTickMsg{}, nil) == nil {
+		t.Error("no read after the last one came back")
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	*diagnostics = Diagnostics{}
+	base.ClearRecentLogs()
+	h := open(t, NewDiagnostics())
+	h.golden("nothing")
+
+	uiLog.Info("not worth showing")
+	dbusLog.Warn("hardware status incomplete", "err", "line 5: temperature")
+	systemdLog.Error("unit status failed", "unit", "tor", "err", "\x1b[1mtimeout\x1b[0m")
+	h.mask(`\b\d\d:\d\d:\d\d\b`, "hh:mm:ss")
+	h.golden("recent")
+	h.enter()
+	h.press("enter")
+	h.golden("cleared")
+}
+
+func TestNotifications(t *testing.T) {
+	*notifications = Notifications{}
+	notices, toasts = nil, nil
+	t.Cleanup(func() { notices, toasts = nil, nil })
+	h := open(t, NewNotifications())
+	h.golden("nothing")
+
+	Notify(Notice{Time: time.Date(2026, 3, 14, 9, 26, 0, 0, time.UTC), Severity: SeverityError,
+		Title: "Services", Message: "monerod failed", Screen: "Services"})
+	h.send(dbus_model.DbusSignalMsg{Signal: dbus_model.PowerButtonPressDetected{}})
+	// status reports aren't news
+	h.send(dbus_model.DbusSignalMsg{Signal: dbus_model.HardwareStatusReadyNotification{}})
+	h.mask(`\d{4}-\d\d-\d\d \d\d:\d\d:\d\d`, "yyyy-mm-dd hh:mm:ss")
+	h.golden("received")
+	if n := Unread("Services"); n != 1 {
+		t.Errorf("%d unread about Services", n)
+	}
+
+	h.enter()
+	if n := Unread(notifications.Label()); n != 0 {
+		t.Errorf("%d unread after viewing", n)
+	}
+	h.press("enter")
+	h.golden("cleared")
+}
+
+// A 95 character address, different by c
+func address(prefix, c byte) string {
+	return string(prefix) + strings.Repeat(string(c), 94)
+}
+
+func TestLightWallet(t *testing.T) {
+	*lightWallet = LightWallet{}
+	clearnetAddr = "192.168.1.50"
+	onionAddr = "nodoexampleexampleexampleexampleexampleexampleexample.onion"
+	i2pAddr = "nodoexampleexampleexampleexampleexampleexample.b32.i2p"
+	admin := &fakeLWS{accounts: lws.LwsListAccounts{
+		Active: []lws.LwsAccount{
+			{Address: address('4', 'A'), ScanHeight: 3_199_000, AccessTime: ago(2 * time.Hour), Status: lws.LwsAccountActive},
+		},
+		Inactive: []lws.LwsAccount{
+			{Address: address('4', 'B'), ScanHeight: 3_100_000, AccessTime: ago(50 * time.Hour), Status: lws.LwsAccountInactive},
+		},
+	}}
+	h := open(t, NewLightWallet(admin))
+	h.golden("accounts")
+	h.enter()
+
+	// the focus starts on Add Wallet
+	admin.err = errors.New("invalid address")
+	h.press("up", "up", "4abc", "down", "deadbeef", "down", "enter")
+	h.golden("add failed")
+	h.press("enter")
+
+	// on to the first account
+	h.mask(absTime, "<time>")
+	h.press("enter")
+	h.golden("account")
+	h.press("enter")
+	h.golden("deactivated")
+	if got := fmt.Sprint(admin.calls); got != fmt.Sprintf("[{add 4abc} {deactivate %s}]", address('4', 'A')) {
+		t.Errorf("calls %s", got)
+	}
+}
+
+func TestMoneropay(t *testing.T) {
+	*mpay = Moneropay{}
+	transactions = nil
+	restoreConfig(t)
+	at := time.Date(2026, 3, 14, 9, 26, 0, 0, time.UTC)
+	store := &fakeStore{
+		health: moneropay.MoneropayHealth{Status: 200, Services: moneropay.MoneropayServices{Walletrpc: true, Sqlite: true}},
+		txs: []i_moneropay.Transaction{
+			{Subaddress: address('8', 'C'), Expected: 1_500_000_000_000, CreatedAt: at},
+			{Subaddress: address('8', 'D'), Expected: 250_000_000_000, CreatedAt: at.Add(time.Hour)},
+		},
+		received: map[string]moneropay.MoneropayReceive{
+			address('8', 'C'): {
+				Amount: moneropay.MoneropayReceiveAmount{
+					Expected: 1_500_000_000_000,
+					Covered:  moneropay.MoneropayReceiveCovered{Total: 1_500_000_000_000, Unlocked: 1_000_000_000_000},
+				},
+				CreatedAt: at,
+				Transactions: []moneropay.MoneropayReceiveTx{
+					{Amount: 1_500_000_000_000, Confirmations: 12, Timestamp: at.Add(10 * time.Minute), TxHash: hash('9')},
+				},
+			},
+		},
+	}
+	h := open(t, NewMoneropay())
+	h.golden("pending")
+	_updateMpay(h.send, store)
+	h.golden("transactions")
+
+	store.health = moneropay.MoneropayHealth{Status: 503, Services: moneropay.MoneropayServices{Sqlite: true}}
+	_updateMpay(h.send, store)
+	h.golden("degraded")
+	store.health = moneropay.MoneropayHealth{}
+	_updateMpay(h.send, store)
+	h.golden("dead")
+
+	h.enter()
+	h.press("down", "enter")
+	h.golden("clear address")
+	h.press("enter")
+	if got := pendingChanges(); got != "moneropay.enabled: TRUE -> FALSE\n" {
+		t.Errorf("pending changes:\n%s", got)
+	}
+}
+
+func TestDropToShell(t *testing.T) {
+	*dropToShell = DropToShell{}
+	opts := base.Opts
+	t.Cleanup(func() { base.Opts = opts })
+	base.Opts.ShellUser, base.Opts.MoneroDir = "sshui-test-nobody", "/nonexistent/monero"
+	h := open(t, NewDropToShell())
+	h.golden("shells")
+	h.enter()
+
+	h.press("down", "enter")
+	h.golden("no user")
+	h.press("enter")
+
+	// the status of the last command in the shell
+	h.send(ShellExitMsg{&exec.ExitError{}})
+	if len(Popups) != 0 {
+		t.Error("popup for a shell's exit status")
+	}
+	h.send(ShellExitMsg{errors.New(`exec: "/bin/nosh": stat /bin/nosh: no such file or directory`)})
+	h.golden("no shell")
+}
+
+func TestFirstBoot(t *testing.T) {
+	*firstBoot = FirstBoot{}
+	bus := newFakeBus()
+	h := open(t, NewFirstBoot(bus))
+	h.golden("empty")
+	h.enter()
+
+	h.press("hunter22", "down", "hunter2")
+	h.golden("mismatch")
+	h.press("2")
+	h.golden("match")
+
+	h.press("down", "enter")
+	h.send(dbus_model.DbusSignalMsg{Signal: dbus_model.PasswordChangeStatus{}})
+	h.golden("set")
+	h.press("enter")
+	h.golden("rebooting")
+	if got := fmt.Sprint(bus.called()); got != "[{setPassword [hunter22]} {restart []}]" {
+		t.Errorf("calls %s", got)
+	}
+}
@@ -0,0 +1,270 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/backend/i_systemd"
+	"github.com/moneronodo/sshui/internal/base"
+)
+
+var services *Services = &Services{}
+
+const (
+	serviceStatusTimeout = 5 * time.Second
+	serviceRowFormat     = "%-11s %-9s %-9s %-10s %9s %8s  %s"
+)
+
+// The units the Nodo runs its services as
+var serviceUnits = []string{"monerod", "tor", "i2pd", "monero-lws", "sshd", "moneropay"}
+
+var (
+	servicesHeader *ScreenLabel
+	servicesList   *ScreenList
+
+	servicesListPane    *ScreenPane
+	servicesActionsPane *ScreenPane
+)
+
+type ServiceStatusMsg struct {
+	Status []i_systemd.UnitStatus
+}
+
+type ServicesTickMsg struct{}
+
+// An enable or disable of unit at boot that finished
+type UnitFileMsg struct {
+	Label string
+	Unit  string
+	Err   error
+}
+
+type Services struct {
+	init    bool
+	manager ServiceManager
+	status  []i_systemd.UnitStatus
+	// the screen is in view, statuses are only read while it is
+	shown bool
+	// a read under way, ticks don't start another
+	fetching bool
+	items    []ScreenItem
+	current  int
+}
+
+func NewServices(manager ServiceManager) *Services {
+	services.manager = manager
+	return services
+}
+
+func unitStatuses(manager ServiceManager) ServiceStatusMsg {
+	ctx, cancel := context.WithTimeout(context.Background(), serviceStatusTimeout)
+	defer cancel()
+	var msg ServiceStatusMsg
+	for _, u := range serviceUnits {
+		st, err := manager.Status(ctx, u)
+		if err != nil {
+			systemdLog.Warn("unit status failed", "unit", u, "err", err)
+			st = i_systemd.UnitStatus{Unit: u, ActiveState: "unknown"}
+		}
+		msg.Status = append(msg.Status, st)
+	}
+	return msg
+}
+
+// Re-reads the unit statuses while the screen is in view
+func UpdateServices(prog *tea.Program) {
+	tick := time.NewTicker(5 * time.Second)
+	defer tick.Stop()
+	for range tick.C {
+		prog.Send(ServicesTickMsg{})
+	}
+}
+
+func (s *Services) refresh() tea.Cmd {
+	if s.fetching {
+		return nil
+	}
+	s.fetching = true
+	manager := s.manager
+	return func() tea.Msg {
+		return unitStatuses(manager)
+	}
+}
+
+func (s *Services) selected() string {
+	return serviceUnits[servicesList.Cursor()]
+}
+
+// Asks before running a start, stop or restart job on the selected unit
+func (s *Services) jobButton(verb jobVerb, color gss.Color) *ScreenButton {
+	return NewScreenButton(verb.name, color, func(sb *ScreenButton) tea.Cmd {
+		unit := s.selected()
+		AddPopup(NewDefaultPopupYesNo(verb.name+" "+unit,
+			fmt.Sprintf("%s %s now?", verb.name, unit), gss.Color(base.CBrightRed),
+			func(sb *ScreenButton) tea.Cmd {
+				if recoveryBlocks(verb.name + " " + unit) {
+					return nil
+				}
+				return runServices(s.manager, verb, []string{unit})
+			}, nil))
+		return nil
+	})
+}
+
+// Asks before enabling or disabling the selected unit at boot
+func (s *Services) unitFileButton(label string, enable bool, color gss.Color) *ScreenButton {
+	return NewScreenButton(label, color, func(sb *ScreenButton) tea.Cmd {
+		unit := s.selected()
+		when := "stop starting"
+		if enable {
+			when = "start"
+		}
+		AddPopup(NewDefaultPopupYesNo(label+" "+unit,
+			fmt.Sprintf("Make %s %s at boot?", unit, when), gss.Color(base.CBrightRed),
+			func(sb *ScreenButton) tea.Cmd {
+				if recoveryBlocks(label + " " + unit) {
+					return nil
+				}
+				fn := s.manager.Disable
+				if enable {
+					fn = s.manager.Enable
+				}
+				return func() tea.Msg {
+					ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+					defer cancel()
+					return UnitFileMsg{label, unit, fn(ctx, unit)}
+				}
+			}, nil))
+		return nil
+	})
+}
+
+func (s *Services) Init() tea.Msg {
+	servicesHeader = NewScreenLabel(fmt.Sprintf(serviceRowFormat,
+		"Service", "State", "Sub", "Uptime", "Memory", "Restarts", "Boot"), gss.Color(base.CGray))
+	servicesList = NewScreenList(len(serviceUnits), gss.Color(base.CWhite),
+		func(sl *ScreenList, i int) tea.Cmd {
+			// on to the actions for the unit
+			return func() tea.Msg {
+				return UpdateFocus(s, 1)
+			}
+		})
+	servicesListPane = NewScreenPane("Services", gss.Color(base.CAqua), servicesHeader, servicesList)
+	servicesActionsPane = NewScreenPane("", gss.Color(base.CBrightAqua),
+		s.jobButton(jobStart, gss.Color(base.CBrightGreen)),
+		s.jobButton(jobStop, gss.Color(base.CRed)),
+		s.jobButton(jobRestart, gss.Color(base.CBrightYellow)),
+		s.unitFileButton("Enable", true, gss.Color(base.CGreen)),
+		s.unitFileButton("Disable", false, gss.Color(base.CRed)),
+	)
+	s.items = append(s.items, servicesListPane, servicesActionsPane)
+	s.setRows()
+	s.init = true
+	return nil
+}
+
+func (s *Services) Label() string {
+	return "Services"
+}
+
+func formatUnitStatus(st i_systemd.UnitStatus) string {
+	uptime, mem := "-", "-"
+	if st.ActiveState == "active" && !st.ActiveSince.IsZero() {
+		uptime = base.FormatSeconds(uint64(time.Since(st.ActiveSince).Seconds()))
+	}
+	if st.Memory > 0 {
+		mem = base.FormatBytes(st.Memory)
+	}
+	return fmt.Sprintf(serviceRowFormat,
+		st.Unit, st.ActiveState, st.SubState, uptime, mem, fmt.Sprint(st.Restarts), st.UnitFileState)
+}
+
+func (s *Services) setRows() {
+	rows := make([]string, len(serviceUnits))
+	for i, u := range serviceUnits {
+		rows[i] = fmt.Sprintf(serviceRowFormat, u, "...", "", "", "", "", "")
+		if i < len(s.status) {
+			rows[i] = formatUnitStatus(s.status[i])
+		}
+	}
+	servicesList.SetRows(rows)
+}
+
+func (s *Services) View() {
+	if !s.init {
+		return
+	}
+	servicesActionsPane.Title = s.selected()
+	// keep the uptimes ticking between status reports
+	s.setRows()
+}
+
+func (s *Services) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case ScreenActiveChangeMsg:
+		s.shown = msg.Screen == s
+		if s.shown {
+			return s.refresh()
+		}
+	case ServicesTickMsg:
+		if s.shown {
+			return s.refresh()
+		}
+	case ServiceStatusMsg:
+		s.status, s.fetching = msg.Status, false
+	case ServiceJobMsg:
+		return s.refresh()
+	case UnitFileMsg:
+		if msg.Err != nil {
+			AddPopup(NewDefaultPopupOK("Couldn't "+msg.Label+" "+msg.Unit, msg.Err.Error(),
+				gss.Color(base.CBrightRed), nil))
+		}
+		return s.refresh()
+	}
+	return nil
+}
+
+func (s *Services) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *Services) Current() *int {
+	return &s.current
+}
+
+func (s *Services) Next() tea.Msg {
+	if servicesList.IsFocus() && servicesList.Scroll(1) {
+		return FocusChangeMsg{Current: servicesListPane}
+	}
+	return UpdateFocus(s, 1)
+}
+
+func (s *Services) Prev() tea.Msg {
+	if servicesList.IsFocus() && servicesList.Scroll(-1) {
+		return FocusChangeMsg{Current: servicesListPane}
+	}
+	return UpdateFocus(s, -1)
+}
+
+func (s *Services) Interact(m tea.Model) tea.Cmd {
+	return s.items[s.current].Interact(m)
+}
+
+func (s *Services) PosVertical() gss.Position {
+	return gss.Position(0.2)
+}
+
+func (s *Services) PosHorizontal() gss.Position {
+	return gss.Center
+}
+
+func (s *Services) ItemWidth() int {
+	return 2
+}
+
+func (s *Services) Vertical() bool {
+	return true
+}
@@ -21,6 +21,9 @@ var (
 	settingsPrivacyPane *ScreenPane
 
 	privateRPCToggle *ScreenToggle
+	boogToggle       *ScreenToggle
+	dnsToggle        *ScreenToggle
+	guixmrToggle     *ScreenToggle
 )
 
 type Settings struct {
@@ -36,10 +39,15 @@ func NewSettings() *Settings {
 func newBanlistToggle(label, val string) *ScreenToggle {
 	toggle := NewScreenToggle(label, gss.Color(base.CGreen),
 		func(sb *ScreenToggle, toggled bool) tea.Cmd {
-			base.SetBanlistConfig(val, toggled)
-			return base.SaveConfigFile
+			err := base.SetBanlistConfig(val, toggled)
+			if err != nil {
+				sb.toggled = !toggled
+			}
+			return configResult(err)
 		})
-	toggle.toggled, _ = base.GetVal("banlists", val).(bool)
+	bindConfig("banlists."+val, func() {
+		toggle.toggled, _ = base.GetVal("banlists", val).(bool)
+	})
 	return toggle
 }
 
@@ -52,13 +60,11 @@ func (s *Settings) Init() tea.Msg {
 	privateRPCToggle = newToggle("RPC Authentication", "rpc_enabled")
 	rpcUserButton = newInputStrBtn("RPC Username", "rpcu", false)
 	rpcPassButton = newInputStrBtn("RPC Password", "rpcp", true)
+	boogToggle = newBanlistToggle("Boog900", "boog900")
+	dnsToggle = newBanlistToggle("DNS", "dns")
+	guixmrToggle = newBanlistToggle("gui.xmr.pm", "gui-xmr-pm")
 	banlistButton = NewScreenButton("Banlist Settings", gss.Color(base.CBrightYellow),
 		func(sb *ScreenButton) tea.Cmd {
-			var (
-				boogToggle   = newBanlistToggle("Boog900", "boog900")
-				dnsToggle    = newBanlistToggle("DNS", "dns")
-				guixmrToggle = newBanlistToggle("gui.xmr.pm", "gui-xmr-pm")
-			)
 			AddPopup(NewDefaultPopupOK("Banlist Settings", "", gss.Color(base.CBrightYellow),
 				func(sb *ScreenButton) tea.Cmd {
 					return nil
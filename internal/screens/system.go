@@ -7,6 +7,7 @@ import (
 	gss "github.com/charmbracelet/lipgloss"
 	"github.com/moneronodo/sshui/internal/backend/i_dbus"
 	"github.com/moneronodo/sshui/internal/base"
+	"github.com/moneronodo/sshui/internal/model/dbus"
 	// "github.com/moneronodo/sshui/internal/base"
 )
 
@@ -15,79 +16,59 @@ var system *System = &System{}
 var (
 	rebootButton   *ScreenButton
 	shutdownButton *ScreenButton
-	recoveryButton *ScreenButton
 
-	sysPane              *ScreenPane
-	recoveryFSToggle     *ScreenToggle
-	recoveryResyncToggle *ScreenToggle
+	sysPane *ScreenPane
 )
 
 type System struct {
 	init    bool
+	bus     SystemBus
 	items   []ScreenItem
 	current int
 }
 
-func NewSystem() *System {
+func NewSystem(bus SystemBus) *System {
+	system.bus = bus
 	return system
 }
 
 func (s *System) Init() tea.Msg {
 	rebootButton = NewScreenButton("Reboot", gss.Color(base.CYellow), func(sb *ScreenButton) tea.Cmd {
+		if recoveryBlocks("Rebooting") {
+			return nil
+		}
 		AddPopup(NewDefaultPopupYesNo("Restart", "Are you sure?", gss.Color(base.CBrightRed),
 			func(sb *ScreenButton) tea.Cmd {
-				i_dbus.Call("restart")
-				return nil
+				return i_dbus.CallCmd(s.bus, i_dbus.Restart)
 			},
 			nil,
 		))
 		return nil
 	})
 	shutdownButton = NewScreenButton("Shutdown", gss.Color(base.CRed), func(sb *ScreenButton) tea.Cmd {
+		if recoveryBlocks("Shutting down") {
+			return nil
+		}
 		AddPopup(NewDefaultPopupYesNo("Shutdown", "Are you sure?", gss.Color(base.CBrightRed),
 			func(sb *ScreenButton) tea.Cmd {
-				i_dbus.Call("shutdown")
-				return nil
+				return i_dbus.CallCmd(s.bus, i_dbus.Shutdown)
 			},
 			nil,
 		))
 		return nil
 	})
-	recoveryFSToggle = NewScreenToggle(
-		"Recover Filesystem",
-		gss.Color(base.CYellow),
-		nil,
-	)
-
-	recoveryResyncToggle = NewScreenToggle(
-		"Purge & Resync Blockchain",
-		gss.Color(base.CYellow),
-		nil,
-	)
-	recoveryButton = NewScreenButton("Start Recovery", gss.Color(base.CBrightPurple), func(sb *ScreenButton) tea.Cmd {
-		AddPopup(NewDefaultPopupOKCancel("Recovery", "Select your recovery options, then press OK.", gss.Color(base.CYellow),
-			func(sb *ScreenButton) tea.Cmd {
-				i_dbus.Call("startRecovery", recoveryFSToggle.toggled, recoveryResyncToggle.toggled)
-				return nil
-			},
-			nil,
-			recoveryFSToggle,
-			recoveryResyncToggle,
-		))
-		return nil
-	})
-
 	sysPane = NewScreenPane(
 		"Power",
 		gss.Color(base.CAqua),
 		rebootButton,
 		shutdownButton,
-		recoveryButton,
 	)
+	resetPane = newResetPane(s.bus)
 
 	s.items = append(
 		s.items,
 		sysPane,
+		resetPane,
 	)
 	s.init = true
 	UpdateFocus(s, 0)
@@ -104,8 +85,30 @@ func (s *System) View() {
 	}
 }
 
+// Tells how a call to the embedded interface went, nothing on success
+// when done is empty
+func callPopup(msg dbus.DbusCallMsg, title, done string) {
+	if msg.Err != nil {
+		AddPopup(NewDefaultPopupOK(title+" failed", msg.Err.Error(), gss.Color(base.CBrightRed), nil))
+	} else if done != "" {
+		AddPopup(NewDefaultPopupOK(title, done, gss.Color(base.CBrightGreen), nil))
+	}
+}
+
 func (s *System) Update(msg tea.Msg, m tea.Model) tea.Cmd {
-	return nil
+	if !s.init {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case dbus.DbusCallMsg:
+		switch msg.Method {
+		case i_dbus.Restart.Name:
+			callPopup(msg, "Reboot", "Your Nodo is rebooting, this session will end shortly.")
+		case i_dbus.Shutdown.Name:
+			callPopup(msg, "Shutdown", "Your Nodo is shutting down, this session will end shortly.")
+		}
+	}
+	return updateFactoryReset(msg)
 }
 
 func (s *System) Items() []ScreenItem {
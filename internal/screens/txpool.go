@@ -0,0 +1,325 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gss "github.com/charmbracelet/lipgloss"
+	"github.com/moneronodo/sshui/internal/backend/daemonrpc"
+	"github.com/moneronodo/sshui/internal/base"
+	rpc_model "github.com/moneronodo/sshui/internal/model/daemonrpc"
+)
+
+var txPool *TxPool = &TxPool{}
+
+const (
+	txPoolListHeight   = 12
+	txPoolHistoBuckets = 5
+	txPoolHistoWidth   = 30
+)
+
+var (
+	txPoolStatsLabel *ScreenLabel
+	txPoolHistoLabel *ScreenLabel
+	txPoolList       *ScreenList
+	txPoolFlush      *ScreenButton
+
+	txPoolStatsPane *ScreenPane
+	txPoolListPane  *ScreenPane
+)
+
+var txPoolBarStyle = gss.NewStyle().Foreground(gss.Color(base.CBrightAqua))
+
+// The flush of the pool finished
+type TxPoolFlushMsg struct {
+	Err error
+}
+
+type TxPool struct {
+	init   bool
+	daemon DaemonClient
+	stats  rpc_model.TxPoolStats
+	txs    []rpc_model.TxPoolTransaction
+	// the screen is in view, the full pool is only read while it is
+	shown bool
+	// a read of the pool under way
+	fetching bool
+	items    []ScreenItem
+	current  int
+}
+
+func NewTxPool(daemon DaemonClient) *TxPool {
+	txPool.daemon = daemon
+	return txPool
+}
+
+func feeRate(tx rpc_model.TxPoolTransaction) float64 {
+	if tx.Weight == 0 {
+		return 0
+	}
+	return float64(tx.Fee) / float64(tx.Weight)
+}
+
+func txPoolDetails(tx rpc_model.TxPoolTransaction) string {
+	return fmt.Sprintf(
+		"Received: %s (%s)\nFee: %s XMR (%.0f pXMR/B)\nWeight: %d\nSize: %s\n"+
+			"Relayed: %s\nDouble spend seen: %s\nKept by block: %s",
+		base.UnixTime(tx.ReceiveTime),
+		base.UnixTimeRelative(tx.ReceiveTime),
+		xmr(tx.Fee),
+		feeRate(tx),
+		tx.Weight,
+		base.FormatBytes(tx.BlobSize),
+		yesNo(tx.Relayed),
+		yesNo(tx.DoubleSpendSeen),
+		yesNo(tx.KeptByBlock),
+	)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func (s *TxPool) flushTxPool() tea.Cmd {
+	daemon := s.daemon
+	return func() tea.Msg {
+		_, err := daemonrpc.Call(context.Background(), daemon, daemonrpc.FlushTxpool,
+			rpc_model.DaemonRequestParamsFlushTxpool{})
+		return TxPoolFlushMsg{err}
+	}
+}
+
+// Reads the full pool, the statistics come with every RPC update
+func (s *TxPool) fetchPool() tea.Cmd {
+	if s.fetching {
+		return nil
+	}
+	s.fetching = true
+	daemon := s.daemon
+	return func() tea.Msg {
+		return daemonrpc.CallMsg(context.Background(), daemon, daemonrpc.GetTransactionPool,
+			rpc_model.NoParams{})
+	}
+}
+
+func (s *TxPool) fetchStats() tea.Cmd {
+	daemon := s.daemon
+	return func() tea.Msg {
+		return daemonrpc.CallMsg(context.Background(), daemon, daemonrpc.GetTransactionPoolStats,
+			rpc_model.NoParams{})
+	}
+}
+
+func (s *TxPool) Init() tea.Msg {
+	txPoolStatsLabel = NewScreenLabel("", gss.Color(base.CGray))
+	txPoolHistoLabel = NewScreenLabel("", gss.Color(base.CGray))
+	txPoolList = NewScreenList(txPoolListHeight, gss.Color(base.CWhite),
+		func(sl *ScreenList, i int) tea.Cmd {
+			if i >= len(s.txs) {
+				return nil
+			}
+			AddPopup(NewDefaultPopupOK(s.txs[i].IdHash, txPoolDetails(s.txs[i]),
+				gss.Color(base.CGray), nil))
+			return nil
+		})
+	txPoolFlush = NewScreenButton("Flush Pool", gss.Color(base.CRed),
+		func(sb *ScreenButton) tea.Cmd {
+			AddPopup(NewDefaultPopupYesNo("Flush Pool",
+				"Remove all transactions from this node's pool?", gss.Color(base.CBrightRed),
+				func(sb *ScreenButton) tea.Cmd {
+					return s.flushTxPool()
+				}, nil))
+			return nil
+		})
+
+	txPoolStatsPane = NewScreenPane(
+		"Pool Statistics",
+		gss.Color(base.CBrightAqua),
+		txPoolStatsLabel,
+		txPoolHistoLabel,
+		txPoolFlush,
+	)
+	txPoolListPane = NewScreenPane("Pending Transactions", gss.Color(base.CAqua), txPoolList)
+	s.items = append(s.items, txPoolStatsPane, txPoolListPane)
+	s.init = true
+	return nil
+}
+
+func (s *TxPool) Label() string {
+	return "Tx Pool"
+}
+
+func (s *TxPool) feeHistogram() string {
+	if len(s.txs) == 0 {
+		return ""
+	}
+	var (
+		sb     strings.Builder
+		counts [txPoolHistoBuckets]int
+		lo, hi = feeRate(s.txs[0]), feeRate(s.txs[0])
+	)
+	for _, tx := range s.txs {
+		lo = min(lo, feeRate(tx))
+		hi = max(hi, feeRate(tx))
+	}
+	step := (hi - lo) / txPoolHistoBuckets
+	for _, tx := range s.txs {
+		b := txPoolHistoBuckets - 1
+		if step > 0 {
+			b = min(int((feeRate(tx)-lo)/step), txPoolHistoBuckets-1)
+		}
+		counts[b]++
+	}
+	peak := slices.Max(counts[:])
+	sb.WriteString("Fee rate (pXMR/B)\n")
+	for i, c := range counts {
+		if step == 0 && c == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%7.0f-%-7.0f %s %d\n",
+			lo+step*float64(i),
+			lo+step*float64(i+1),
+			txPoolBarStyle.Render(fmt.Sprintf("%-*s", txPoolHistoWidth,
+				strings.Repeat("#", c*txPoolHistoWidth/peak))),
+			c,
+		))
+	}
+	return sb.String()
+}
+
+func (s *TxPool) View() {
+	if !s.init {
+		return
+	}
+	oldest := "-"
+	if s.stats.Oldest > 0 {
+		oldest = base.FormatSeconds(uint64(time.Since(time.Unix(s.stats.Oldest, 0)).Seconds()))
+	}
+	txPoolStatsLabel.label = gss.JoinHorizontal(
+		gss.Top,
+		labelInner.Render(`Transactions
+Total size
+Total fees
+Oldest
+Double spends
+Failing
+Not relayed
+Older than 10m`),
+		labelInner.Render(fmt.Sprintf(`: %d
+: %s
+: %s XMR
+: %s
+: %d
+: %d
+: %d
+: %d`,
+			s.stats.TxsTotal,
+			base.FormatBytes(s.stats.BytesTotal),
+			xmr(s.stats.FeeTotal),
+			oldest,
+			s.stats.NumDoubleSpends,
+			s.stats.NumFailing,
+			s.stats.NumNotRelayed,
+			s.stats.Num10m,
+		)),
+	)
+	txPoolHistoLabel.label = s.feeHistogram()
+}
+
+func (s *TxPool) Update(msg tea.Msg, m tea.Model) tea.Cmd {
+	switch m := msg.(type) {
+	case ScreenActiveChangeMsg:
+		s.shown = m.Screen == s
+		if s.shown {
+			return s.fetchPool()
+		}
+	case TxPoolFlushMsg:
+		if m.Err != nil {
+			AddPopup(NewDefaultPopupOK("Couldn't flush pool",
+				m.Err.Error(), gss.Color(base.CBrightRed), nil))
+			return nil
+		}
+		s.fetching = false
+		return tea.Batch(s.fetchStats(), s.fetchPool())
+	case rpc_model.DaemonRPCMsg[rpc_model.DaemonResponseBodyGetTransactionPoolStats]:
+		if m.Err == nil {
+			s.stats = m.Result.PoolStats
+		}
+		// the pool along with the statistics while it is in view
+		if s.shown {
+			return s.fetchPool()
+		}
+	case rpc_model.DaemonRPCMsg[rpc_model.DaemonResponseBodyGetTransactionPool]:
+		s.fetching = false
+		if m.Err != nil {
+			return nil
+		}
+		s.txs = m.Result.Transactions
+		slices.SortFunc(s.txs, func(a, b rpc_model.TxPoolTransaction) int {
+			return int(a.ReceiveTime - b.ReceiveTime)
+		})
+		if !s.init {
+			return nil
+		}
+		rows := make([]string, len(s.txs))
+		for i, tx := range s.txs {
+			rows[i] = fmt.Sprintf("%s  %s XMR  %6d  %s",
+				tx.IdHash,
+				xmr(tx.Fee),
+				tx.Weight,
+				base.UnixTimeRelative(tx.ReceiveTime),
+			)
+		}
+		txPoolList.SetRows(rows)
+	}
+	return nil
+}
+
+func (s *TxPool) Items() []ScreenItem {
+	return s.items
+}
+
+func (s *TxPool) Current() *int {
+	return &s.current
+}
+
+func (s *TxPool) Next() tea.Msg {
+	if txPoolList.IsFocus() && txPoolList.Scroll(1) {
+		return FocusChangeMsg{Current: txPoolListPane}
+	}
+	return UpdateFocus(s, 1)
+}
+
+func (s *TxPool) Prev() tea.Msg {
+	if txPoolList.IsFocus() && txPoolList.Scroll(-1) {
+		return FocusChangeMsg{Current: txPoolListPane}
+	}
+	return UpdateFocus(s, -1)
+}
+
+func (s *TxPool) Interact(m tea.Model) tea.Cmd {
+	return s.items[s.current].Interact(m)
+}
+
+func (s *TxPool) PosVertical() gss.Position {
+	return gss.Position(0.2)
+}
+
+func (s *TxPool) PosHorizontal() gss.Position {
+	return gss.Center
+}
+
+func (s *TxPool) ItemWidth() int {
+	return 2
+}
+
+func (s *TxPool) Vertical() bool {
+	return true
+}